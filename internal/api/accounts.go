@@ -0,0 +1,22 @@
+package api
+
+import "context"
+
+// AccountsService wraps the /v1/accounts endpoint.
+type AccountsService struct {
+	client *Client
+}
+
+// Accounts returns the accounts service.
+func (c *Client) Accounts() *AccountsService {
+	return &AccountsService{client: c}
+}
+
+// List returns the connected chat networks.
+func (s *AccountsService) List(ctx context.Context) ([]Account, error) {
+	var accounts []Account
+	if err := s.client.getJSON(ctx, "/v1/accounts", nil, "", &accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
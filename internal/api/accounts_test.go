@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+func TestAccountsList(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		testutil.JSONResponse(w, http.StatusOK, `[{"id":"whatsapp","networkName":"WhatsApp"}]`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	accounts, err := client.Accounts().List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].NetworkName != "WhatsApp" {
+		t.Errorf("accounts = %+v, want one WhatsApp account", accounts)
+	}
+}
+
+func TestAccountsListUnauthorized(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	client := NewClient(server.URL, "bad-token")
+	if _, err := client.Accounts().List(context.Background()); err == nil {
+		t.Error("List() should fail with an invalid token")
+	}
+}
+
+func TestFocus(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/focus" {
+			t.Errorf("Path = %q, want '/v1/focus'", r.URL.Path)
+		}
+		var body FocusRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.ChatID != "!a" || body.DraftText != "hi" {
+			t.Errorf("body = %+v, want chat !a with draft 'hi'", body)
+		}
+		testutil.JSONResponse(w, http.StatusOK, `{"success":true}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	if err := client.Focus(context.Background(), FocusRequest{ChatID: "!a", DraftText: "hi"}); err != nil {
+		t.Fatalf("Focus() error: %v", err)
+	}
+}
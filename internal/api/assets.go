@@ -0,0 +1,199 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxUploadSize is the largest file Upload accepts unless the caller
+// sets a limit. Networks may enforce lower limits of their own.
+const DefaultMaxUploadSize = 100 << 20
+
+// ErrUploadUnsupported reports that Beeper Desktop has no upload endpoint.
+var ErrUploadUnsupported = errors.New("this Beeper Desktop version does not support uploads")
+
+// AssetsService wraps the asset upload endpoint.
+type AssetsService struct {
+	client *Client
+}
+
+// Assets returns the assets service.
+func (c *Client) Assets() *AssetsService {
+	return &AssetsService{client: c}
+}
+
+// UploadedAsset identifies an uploaded file for use in a message.
+type UploadedAsset struct {
+	UploadID string `json:"uploadID"`
+	FileName string `json:"fileName"`
+	MimeType string `json:"mimeType"`
+	FileSize int64  `json:"fileSize"`
+	SrcURL   string `json:"srcURL,omitempty"`
+}
+
+// File is a local file prepared for upload.
+type File struct {
+	Path     string
+	Name     string
+	MimeType string
+	Size     int64
+}
+
+// OpenFile checks that path is a readable, non-empty regular file no larger
+// than maxSize bytes (0 means DefaultMaxUploadSize) and detects its MIME type.
+func OpenFile(path string, maxSize int64) (*File, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxUploadSize
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read attachment: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("attachment %s is not a regular file", path)
+	}
+	if info.Size() == 0 {
+		return nil, fmt.Errorf("attachment %s is empty", path)
+	}
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("attachment %s is %s, over the %s limit", path, FormatSize(info.Size()), FormatSize(maxSize))
+	}
+
+	mimeType, err := DetectMIME(path)
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Path: abs, Name: filepath.Base(path), MimeType: mimeType, Size: info.Size()}, nil
+}
+
+// DetectMIME returns a file's MIME type from its extension, or by sniffing
+// its content when the extension is unknown.
+func DetectMIME(path string) (string, error) {
+	if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(path))); t != "" {
+		return t, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read attachment: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("cannot read attachment: %w", err)
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// FormatSize renders a byte count for humans, e.g. "4.2 MB".
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// Upload sends a file to Beeper Desktop as multipart form data. It returns
+// ErrUploadUnsupported when the endpoint doesn't exist.
+func (s *AssetsService) Upload(ctx context.Context, file *File) (*UploadedAsset, error) {
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read attachment: %w", err)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": "file", "filename": file.Name}))
+	header.Set("Content-Type", file.MimeType)
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+	_ = mw.WriteField("fileName", file.Name)
+	_ = mw.WriteField("mimeType", file.MimeType)
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.postRaw(ctx, "/v1/assets/upload", body.Bytes(), mw.FormDataContentType())
+	if err != nil {
+		return nil, UserFriendlyError(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, ErrUploadUnsupported
+	}
+
+	var asset UploadedAsset
+	if err := decodeResponse(resp, "", &asset); err != nil {
+		return nil, fmt.Errorf("failed to upload %s: %w", file.Name, err)
+	}
+	if asset.UploadID == "" {
+		return nil, fmt.Errorf("failed to upload %s: no upload ID in response", file.Name)
+	}
+	if asset.MimeType == "" {
+		asset.MimeType = file.MimeType
+	}
+	if asset.FileName == "" {
+		asset.FileName = file.Name
+	}
+	return &asset, nil
+}
+
+// SendFiles uploads files and sends one message per file to a chat, with
+// text as the caption of the first. Every upload finishes before anything is
+// sent, so an ErrUploadUnsupported or upload failure sends nothing.
+func (s *MessagesService) SendFiles(ctx context.Context, chatID string, req SendMessageRequest, files []*File) ([]SendMessageResponse, error) {
+	assets := make([]*UploadedAsset, 0, len(files))
+	for _, f := range files {
+		asset, err := s.client.Assets().Upload(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+
+	results := make([]SendMessageResponse, 0, len(assets))
+	for i, asset := range assets {
+		msg := SendMessageRequest{
+			Attachment: &MessageAttachment{UploadID: asset.UploadID, FileName: asset.FileName, MimeType: asset.MimeType},
+		}
+		if i == 0 {
+			msg.Text = req.Text
+			msg.ReplyToMessageID = req.ReplyToMessageID
+		}
+		result, err := s.Send(ctx, chatID, msg)
+		if err != nil {
+			return results, fmt.Errorf("failed to send %s: %w", asset.FileName, err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestOpenFileChecks(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+
+	f, err := OpenFile(writeFile(t, "noext", png), 0)
+	if err != nil {
+		t.Fatalf("OpenFile() error: %v", err)
+	}
+	if f.MimeType != "image/png" || f.Name != "noext" || f.Size != int64(len(png)) {
+		t.Errorf("OpenFile() = %+v, want sniffed image/png", f)
+	}
+
+	f, err = OpenFile(writeFile(t, "report.PDF", []byte("%PDF-1.4")), 0)
+	if err != nil || f.MimeType != "application/pdf" {
+		t.Errorf("OpenFile(report.PDF) = %+v, %v; want application/pdf", f, err)
+	}
+
+	if _, err := OpenFile(writeFile(t, "big.bin", make([]byte, 2048)), 1024); err == nil || !strings.Contains(err.Error(), "2.0 KB") {
+		t.Errorf("OpenFile() over limit = %v, want size error", err)
+	}
+	if _, err := OpenFile(writeFile(t, "empty.txt", nil), 0); err == nil {
+		t.Error("OpenFile() should reject an empty file")
+	}
+	if _, err := OpenFile(t.TempDir(), 0); err == nil {
+		t.Error("OpenFile() should reject a directory")
+	}
+	if _, err := OpenFile(filepath.Join(t.TempDir(), "missing"), 0); err == nil {
+		t.Error("OpenFile() should reject a missing file")
+	}
+}
+
+func TestSendFilesUploadsThenSends(t *testing.T) {
+	var sent []SendMessageRequest
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/assets/upload":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("not multipart: %v", err)
+			}
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("no file part: %v", err)
+			}
+			data, _ := io.ReadAll(file)
+			if header.Header.Get("Content-Type") != "text/plain; charset=utf-8" || string(data) != "notes" {
+				t.Errorf("part = %q (%s)", data, header.Header.Get("Content-Type"))
+			}
+			testutil.JSONResponse(w, http.StatusOK, `{"uploadID":"up-`+header.Filename+`"}`)
+		case "/v1/chats/c1/messages":
+			var body SendMessageRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			sent = append(sent, body)
+			testutil.JSONResponse(w, http.StatusOK, `{"messageID":"m"}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	a, _ := OpenFile(writeFile(t, "a.txt", []byte("notes")), 0)
+	b, _ := OpenFile(writeFile(t, "b.txt", []byte("notes")), 0)
+
+	client := NewClient(server.URL, "test-token")
+	results, err := client.Messages().SendFiles(context.Background(), "c1", SendMessageRequest{Text: "see attached"}, []*File{a, b})
+	if err != nil {
+		t.Fatalf("SendFiles() error: %v", err)
+	}
+	if len(results) != 2 || len(sent) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(sent))
+	}
+	if sent[0].Text != "see attached" || sent[0].Attachment.UploadID != "up-a.txt" || sent[0].Attachment.MimeType != "text/plain; charset=utf-8" {
+		t.Errorf("first message = %+v, want caption and first upload", sent[0])
+	}
+	if sent[1].Text != "" || sent[1].Attachment.UploadID != "up-b.txt" {
+		t.Errorf("second message = %+v, want second upload without caption", sent[1])
+	}
+}
+
+func TestSendFilesUnsupported(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/assets/upload" {
+			t.Errorf("nothing should be sent, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	f, _ := OpenFile(writeFile(t, "a.txt", []byte("notes")), 0)
+	client := NewClient(server.URL, "test-token")
+	_, err := client.Messages().SendFiles(context.Background(), "c1", SendMessageRequest{}, []*File{f})
+	if !errors.Is(err, ErrUploadUnsupported) {
+		t.Errorf("SendFiles() error = %v, want ErrUploadUnsupported", err)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 1536: "1.5 KB", 100 << 20: "100.0 MB"} {
+		if got := FormatSize(n); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
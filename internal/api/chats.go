@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"net/url"
+)
+
+// ChatsService wraps the /v1/chats endpoints.
+type ChatsService struct {
+	client *Client
+}
+
+// Chats returns the chats service.
+func (c *Client) Chats() *ChatsService {
+	return &ChatsService{client: c}
+}
+
+// ListChatsParams filters and pages a chat listing.
+type ListChatsParams struct {
+	Inbox      string // primary, low-priority or archive
+	AccountIDs []string
+	Cursor     string
+	Direction  string // before or after
+}
+
+func (p ListChatsParams) values() url.Values {
+	params := url.Values{}
+	if p.Inbox != "" {
+		params.Set("inbox", p.Inbox)
+	}
+	addAll(params, "accountIDs", p.AccountIDs)
+	if p.Cursor != "" {
+		params.Set("cursor", p.Cursor)
+	}
+	if p.Direction != "" {
+		params.Set("direction", p.Direction)
+	}
+	return params
+}
+
+// SearchChatsParams filters a chat search.
+type SearchChatsParams struct {
+	Query      string
+	AccountIDs []string
+}
+
+// List returns one page of chats.
+func (s *ChatsService) List(ctx context.Context, params ListChatsParams) (*ListChatsResponse, error) {
+	var result ListChatsResponse
+	if err := s.client.getJSON(ctx, "/v1/chats", params.values(), "", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Get returns a single chat by ID.
+func (s *ChatsService) Get(ctx context.Context, chatID string) (*Chat, error) {
+	var chat Chat
+	if err := s.client.getJSON(ctx, chatPath(chatID, ""), nil, "Chat", &chat); err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+// Search finds chats by title or participant name.
+func (s *ChatsService) Search(ctx context.Context, params SearchChatsParams) (*ListChatsResponse, error) {
+	values := url.Values{}
+	values.Set("query", params.Query)
+	addAll(values, "accountIDs", params.AccountIDs)
+
+	var result ListChatsResponse
+	if err := s.client.getJSON(ctx, "/v1/chats/search", values, "", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Archive archives or unarchives a chat.
+func (s *ChatsService) Archive(ctx context.Context, chatID string, archived bool) error {
+	body := map[string]bool{"archived": archived}
+	return s.client.postJSON(ctx, chatPath(chatID, "/archive"), body, "Chat", nil)
+}
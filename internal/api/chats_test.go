@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+func TestChatsList(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chats" {
+			t.Errorf("Path = %q, want '/v1/chats'", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("inbox") != "primary" {
+			t.Errorf("inbox = %q, want 'primary'", q.Get("inbox"))
+		}
+		if got := q["accountIDs"]; len(got) != 2 || got[0] != "whatsapp" || got[1] != "telegram" {
+			t.Errorf("accountIDs = %v, want [whatsapp telegram]", got)
+		}
+		testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"!a","title":"Alice"}],"hasMore":true,"cursor":"c1"}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	result, err := client.Chats().List(context.Background(), ListChatsParams{
+		Inbox:      "primary",
+		AccountIDs: []string{"whatsapp", "telegram"},
+	})
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(result.Items) != 1 || result.Items[0].Title != "Alice" {
+		t.Errorf("Items = %+v, want one chat titled Alice", result.Items)
+	}
+	if !result.HasMore || result.Cursor != "c1" {
+		t.Errorf("HasMore/Cursor = %v/%q, want true/'c1'", result.HasMore, result.Cursor)
+	}
+}
+
+func TestChatsGetEscapesID(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/v1/chats/%21abc:beeper.com%2Fx" {
+			t.Errorf("EscapedPath = %q", r.URL.EscapedPath())
+		}
+		testutil.JSONResponse(w, http.StatusOK, `{"id":"!abc:beeper.com/x","title":"Team"}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	chat, err := client.Chats().Get(context.Background(), "!abc:beeper.com/x")
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if chat.Title != "Team" {
+		t.Errorf("Title = %q, want 'Team'", chat.Title)
+	}
+}
+
+func TestChatsGetNotFound(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		testutil.JSONResponse(w, http.StatusNotFound, `{"message":"no such chat"}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	_, err := client.Chats().Get(context.Background(), "!missing")
+	if err == nil || err.Error() != "Chat not found" {
+		t.Errorf("Get() error = %v, want 'Chat not found'", err)
+	}
+	if !IsNotFound(err) || IsUnauthorized(err) {
+		t.Errorf("IsNotFound(%v) = false, want the status to survive the friendly message", err)
+	}
+}
+
+func TestChatsSearch(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chats/search" {
+			t.Errorf("Path = %q, want '/v1/chats/search'", r.URL.Path)
+		}
+		if r.URL.Query().Get("query") != "john" {
+			t.Errorf("query = %q, want 'john'", r.URL.Query().Get("query"))
+		}
+		testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"!j","title":"John"}]}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	result, err := client.Chats().Search(context.Background(), SearchChatsParams{Query: "john"})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if len(result.Items) != 1 {
+		t.Errorf("len(Items) = %d, want 1", len(result.Items))
+	}
+}
+
+func TestChatsArchive(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %q, want POST", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/archive") {
+			t.Errorf("Path = %q, want suffix '/archive'", r.URL.Path)
+		}
+		var body map[string]bool
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["archived"] {
+			t.Error("archived = true, want false for unarchive")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	if err := client.Chats().Archive(context.Background(), "!a", false); err != nil {
+		t.Fatalf("Archive() error: %v", err)
+	}
+}
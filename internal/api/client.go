@@ -245,6 +245,21 @@ func (c *Client) Post(ctx context.Context, path string, body any) (*http.Respons
 	return c.Do(ctx, req)
 }
 
+// postRaw POSTs a pre-encoded body with its own content type, such as
+// multipart form data.
+func (c *Client) postRaw(ctx context.Context, path string, body []byte, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", contentType)
+	return c.doWithRetry(ctx, req)
+}
+
 func (c *Client) Delete(ctx context.Context, path string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+path, nil)
 	if err != nil {
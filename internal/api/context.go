@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// DefaultFindScan is how many messages Find reads by default.
+const DefaultFindScan = 2000
+
+// contextMaxPages bounds the pages read on each side of a message.
+const contextMaxPages = 5
+
+// ErrMessageNotFound is returned when Find gives up.
+var ErrMessageNotFound = errors.New("message not found")
+
+// MessageContext is a message with the messages around it, oldest first.
+type MessageContext struct {
+	Message Message   `json:"message"`
+	Chat    *Chat     `json:"chat,omitempty"`
+	Before  []Message `json:"before"`
+	After   []Message `json:"after"`
+}
+
+// Find looks a message up by ID. There is no endpoint for a single
+// message, so the chat's history is read newest first, up to maxScan
+// messages (0 means DefaultFindScan).
+func (s *MessagesService) Find(ctx context.Context, chatID, messageID string, maxScan int) (*Message, error) {
+	if maxScan <= 0 {
+		maxScan = DefaultFindScan
+	}
+	for m, err := range s.All(ctx, chatID, ListMessagesParams{}, maxScan) {
+		if err != nil {
+			return nil, err
+		}
+		if m.ID == messageID {
+			return &m, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s in the latest %d messages", ErrMessageNotFound, messageID, maxScan)
+}
+
+// Context returns up to n messages on either side of m, read with before
+// and after cursors from m's sort key. A message without a sort key, as
+// some search results are, is first looked up with Find.
+func (s *MessagesService) Context(ctx context.Context, m Message, n int) (*MessageContext, error) {
+	mc := &MessageContext{Message: m, Before: []Message{}, After: []Message{}}
+	if n <= 0 {
+		return mc, nil
+	}
+	if m.SortKey == "" {
+		found, err := s.Find(ctx, m.ChatID, m.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		m.SortKey = found.SortKey
+		if m.SortKey == "" {
+			return nil, fmt.Errorf("message %s has no sort key to page from", m.ID)
+		}
+	}
+
+	before, err := s.side(ctx, m, "before", n)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.side(ctx, m, "after", n)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the n closest on each side.
+	mc.Before = before[max(len(before)-n, 0):]
+	mc.After = after[:min(n, len(after))]
+	return mc, nil
+}
+
+// side reads messages in one direction from m until it has at least n,
+// returning them oldest first without m itself.
+func (s *MessagesService) side(ctx context.Context, m Message, direction string, n int) ([]Message, error) {
+	var (
+		items  []Message
+		cursor = m.SortKey
+	)
+	for range contextMaxPages {
+		page, err := s.List(ctx, m.ChatID, ListMessagesParams{Cursor: cursor, Direction: direction})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			if item.ID != m.ID {
+				items = append(items, item)
+			}
+		}
+		if len(items) >= n || !page.HasMore || page.Cursor == "" || page.Cursor == cursor {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	slices.SortStableFunc(items, func(a, b Message) int { return a.Timestamp.Compare(b.Timestamp) })
+	items = slices.CompactFunc(items, func(a, b Message) bool { return a.ID == b.ID })
+	return items, nil
+}
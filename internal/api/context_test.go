@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+// historyServer serves nine messages m1..m9 (sort keys "1".."9") three per
+// page, newest first, honouring before/after cursors.
+func historyServer(t *testing.T) *Client {
+	t.Helper()
+	base := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	var all []Message
+	for i := 1; i <= 9; i++ {
+		all = append(all, Message{ID: fmt.Sprintf("m%d", i), ChatID: "!c", SortKey: fmt.Sprint(i), Timestamp: base.Add(time.Duration(i) * time.Minute)})
+	}
+
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		cursor, direction := r.URL.Query().Get("cursor"), r.URL.Query().Get("direction")
+		var items []Message
+		for _, m := range all {
+			switch {
+			case cursor == "",
+				direction == "after" && m.SortKey > cursor,
+				direction != "after" && m.SortKey < cursor:
+				items = append(items, m)
+			}
+		}
+		if direction == "after" {
+			items = items[:min(3, len(items))]
+		} else {
+			items = items[max(len(items)-3, 0):]
+		}
+		slices.Reverse(items)
+
+		resp := ListMessagesResponse{Items: items}
+		if len(items) > 0 {
+			edge := items[len(items)-1] // oldest on the page
+			if direction == "after" {
+				edge = items[0]
+			}
+			resp.Cursor = edge.SortKey
+			resp.HasMore = edge.ID != "m1" && edge.ID != "m9"
+		}
+		data, _ := json.Marshal(resp)
+		testutil.JSONResponse(w, http.StatusOK, string(data))
+	})
+	return NewClient(server.URL, "test-token")
+}
+
+func ids(msgs []Message) []string {
+	out := make([]string, len(msgs))
+	for i, m := range msgs {
+		out[i] = m.ID
+	}
+	return out
+}
+
+func TestMessagesContext(t *testing.T) {
+	client := historyServer(t)
+	ctx := context.Background()
+
+	m, err := client.Messages().Find(ctx, "!c", "m5", 0)
+	if err != nil || m.SortKey != "5" {
+		t.Fatalf("Find() = %+v, %v", m, err)
+	}
+
+	mc, err := client.Messages().Context(ctx, *m, 4)
+	if err != nil {
+		t.Fatalf("Context() error: %v", err)
+	}
+	if got := ids(mc.Before); !slices.Equal(got, []string{"m1", "m2", "m3", "m4"}) {
+		t.Errorf("Before = %v", got)
+	}
+	if got := ids(mc.After); !slices.Equal(got, []string{"m6", "m7", "m8", "m9"}) {
+		t.Errorf("After = %v", got)
+	}
+
+	// Near the end, and for a hit without a sort key.
+	mc, err = client.Messages().Context(ctx, Message{ID: "m8", ChatID: "!c"}, 2)
+	if err != nil {
+		t.Fatalf("Context() error: %v", err)
+	}
+	if got := ids(mc.Before); !slices.Equal(got, []string{"m6", "m7"}) {
+		t.Errorf("Before = %v", got)
+	}
+	if got := ids(mc.After); !slices.Equal(got, []string{"m9"}) {
+		t.Errorf("After = %v", got)
+	}
+}
+
+func TestMessagesFindNotFound(t *testing.T) {
+	client := historyServer(t)
+	_, err := client.Messages().Find(context.Background(), "!c", "nope", 5)
+	if !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("Find() error = %v, want ErrMessageNotFound", err)
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// unixBaseURL is the placeholder host used for requests over a unix socket.
+// The transport ignores it and dials the socket instead.
+const unixBaseURL = "http://localhost"
+
+// WithTransport replaces the HTTP transport, e.g. for custom TLS or a unix socket.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// ParseEndpoint resolves a user-supplied API address into a base URL and the
+// client options needed to reach it. Supported forms:
+//
+//	http://host:port
+//	https://host:port        (caCert optionally names a PEM bundle to trust)
+//	unix:///path/to/socket
+//
+// An empty address selects DefaultBaseURL.
+func ParseEndpoint(address, caCert string) (string, []ClientOption, error) {
+	if address == "" {
+		address = DefaultBaseURL
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid API URL %q: %w", address, err)
+	}
+
+	switch u.Scheme {
+	case "http":
+		if caCert != "" {
+			return "", nil, fmt.Errorf("CA bundle %s requires an https API URL", caCert)
+		}
+		return strings.TrimSuffix(address, "/"), nil, nil
+	case "https":
+		var opts []ClientOption
+		if caCert != "" {
+			transport, err := caTransport(caCert)
+			if err != nil {
+				return "", nil, err
+			}
+			opts = append(opts, WithTransport(transport))
+		}
+		return strings.TrimSuffix(address, "/"), opts, nil
+	case "unix":
+		socket := u.Path
+		if socket == "" {
+			socket = u.Opaque
+		}
+		if socket == "" {
+			return "", nil, fmt.Errorf("invalid API URL %q: missing socket path", address)
+		}
+		return unixBaseURL, []ClientOption{WithTransport(unixTransport(socket))}, nil
+	default:
+		return "", nil, fmt.Errorf("invalid API URL %q: scheme must be http, https or unix", address)
+	}
+}
+
+// caTransport returns a transport that trusts the certificates in pemFile in
+// addition to the system roots.
+func caTransport(pemFile string) (*http.Transport, error) {
+	pem, err := os.ReadFile(pemFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", pemFile)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	return transport, nil
+}
+
+// unixTransport returns a transport that sends every request over socket.
+func unixTransport(socket string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socket)
+	}
+	return transport
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseEndpointDefaults(t *testing.T) {
+	baseURL, opts, err := ParseEndpoint("", "")
+	if err != nil {
+		t.Fatalf("ParseEndpoint() error: %v", err)
+	}
+	if baseURL != DefaultBaseURL || len(opts) != 0 {
+		t.Errorf("ParseEndpoint(\"\") = %q, %d opts; want %q, 0 opts", baseURL, len(opts), DefaultBaseURL)
+	}
+}
+
+func TestParseEndpointRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		address string
+		caCert  string
+	}{
+		{"ftp://localhost:1", ""},
+		{"unix://", ""},
+		{"http://localhost:1", "/tmp/ca.pem"},
+		{"https://localhost:1", "/nonexistent/ca.pem"},
+	}
+
+	for _, tt := range tests {
+		if _, _, err := ParseEndpoint(tt.address, tt.caCert); err == nil {
+			t.Errorf("ParseEndpoint(%q, %q) should fail", tt.address, tt.caCert)
+		}
+	}
+}
+
+func TestParseEndpointHTTPSWithCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	baseURL, opts, err := ParseEndpoint(server.URL, caFile)
+	if err != nil {
+		t.Fatalf("ParseEndpoint() error: %v", err)
+	}
+
+	resp, err := NewClient(baseURL, "test-token", opts...).Get(context.Background(), "/v1/accounts")
+	if err != nil {
+		t.Fatalf("Get() over https with CA bundle error: %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestParseEndpointUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "beeper.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/accounts" {
+			t.Errorf("Path = %q, want '/v1/accounts'", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	})}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	baseURL, opts, err := ParseEndpoint("unix://"+socket, "")
+	if err != nil {
+		t.Fatalf("ParseEndpoint() error: %v", err)
+	}
+
+	resp, err := NewClient(baseURL, "test-token", opts...).Get(context.Background(), "/v1/accounts")
+	if err != nil {
+		t.Fatalf("Get() over unix socket error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Inboxes are the inboxes a full chat scan walks, in order.
+var Inboxes = []string{"primary", "low-priority", "archive"}
+
+// EnumerateParams selects which chats a full scan collects.
+type EnumerateParams struct {
+	Inboxes    []string // defaults to Inboxes
+	AccountIDs []string
+}
+
+// InboxScan reports how far a scan got through one inbox.
+type InboxScan struct {
+	Inbox    string `json:"inbox"`
+	Chats    int    `json:"chats"`
+	Pages    int    `json:"pages"`
+	Complete bool   `json:"complete"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ChatScan is every chat found by Enumerate, plus per-inbox completeness.
+type ChatScan struct {
+	Chats   []Chat      `json:"chats"`
+	Inboxes []InboxScan `json:"inboxes"`
+}
+
+// Complete reports whether every inbox was walked to its last page.
+func (s *ChatScan) Complete() bool {
+	for _, in := range s.Inboxes {
+		if !in.Complete {
+			return false
+		}
+	}
+	return true
+}
+
+// Summary describes the scan in one line, naming any inbox that failed.
+func (s *ChatScan) Summary() string {
+	summary := fmt.Sprintf("Scanned %d chats across %d inboxes", len(s.Chats), len(s.Inboxes))
+	if s.Complete() {
+		return summary + " (complete)"
+	}
+	var failed []string
+	for _, in := range s.Inboxes {
+		if !in.Complete {
+			failed = append(failed, fmt.Sprintf("%s stopped after %d pages: %s", in.Inbox, in.Pages, in.Error))
+		}
+	}
+	return summary + " (incomplete: " + strings.Join(failed, "; ") + ")"
+}
+
+// Enumerate walks every page of /v1/chats for each inbox separately and
+// returns the de-duplicated set of chats. A failing inbox is recorded in the
+// scan rather than aborting the others; an error is returned only when the
+// context is cancelled or no inbox could be read at all.
+func (s *ChatsService) Enumerate(ctx context.Context, params EnumerateParams) (*ChatScan, error) {
+	inboxes := params.Inboxes
+	if len(inboxes) == 0 {
+		inboxes = Inboxes
+	}
+
+	scan := &ChatScan{}
+	seen := make(map[string]bool)
+	var firstErr error
+	anyPage := false
+
+	for _, inbox := range inboxes {
+		in := InboxScan{Inbox: inbox}
+		last := false // whether the latest page was the inbox's final page
+		list := ListChatsParams{Inbox: inbox, AccountIDs: params.AccountIDs}
+		pages := paginate(ctx, "", 0, func(cursor string) (page[Chat], error) {
+			list.Cursor = cursor
+			result, err := s.List(ctx, list)
+			if err != nil {
+				return page[Chat]{}, err
+			}
+			in.Pages++
+			anyPage = true
+			last = !result.HasMore || result.Cursor == ""
+			return page[Chat]{items: result.Items, hasMore: result.HasMore, cursor: result.Cursor}, nil
+		})
+		var err error
+		for chat, chatErr := range pages {
+			if chatErr != nil {
+				err = chatErr
+				break
+			}
+			in.Chats++
+			if !seen[chat.ID] {
+				seen[chat.ID] = true
+				scan.Chats = append(scan.Chats, chat)
+			}
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return scan, ctxErr
+		}
+		switch {
+		case err != nil:
+			in.Error = err.Error()
+			if firstErr == nil {
+				firstErr = err
+			}
+		case !last:
+			// paginate stops without an error when a cursor repeats.
+			in.Error = "pagination cursor repeated"
+		default:
+			in.Complete = true
+		}
+		scan.Inboxes = append(scan.Inboxes, in)
+	}
+
+	if !anyPage && firstErr != nil {
+		return scan, firstErr
+	}
+	return scan, nil
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+func TestEnumerateWalksEachInbox(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("inbox") + "/" + q.Get("cursor") {
+		case "primary/":
+			testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"1","title":"日本語"},{"id":"2","title":"Алиса"}],"hasMore":true,"cursor":"p2"}`)
+		case "primary/p2":
+			testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"3","title":"🎉"}],"hasMore":false}`)
+		case "low-priority/":
+			testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"4"},{"id":"1"}],"hasMore":false}`)
+		case "archive/":
+			testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"5","isArchived":true}],"hasMore":false}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.RawQuery)
+		}
+	})
+
+	client := NewClient(server.URL, "test-token")
+	scan, err := client.Chats().Enumerate(context.Background(), EnumerateParams{})
+	if err != nil {
+		t.Fatalf("Enumerate() error: %v", err)
+	}
+
+	if len(scan.Chats) != 5 {
+		t.Errorf("len(Chats) = %d, want 5 unique chats", len(scan.Chats))
+	}
+	if !scan.Complete() {
+		t.Errorf("scan should be complete: %s", scan.Summary())
+	}
+	if scan.Inboxes[0].Pages != 2 {
+		t.Errorf("primary pages = %d, want 2", scan.Inboxes[0].Pages)
+	}
+}
+
+func TestEnumerateRecordsFailedInbox(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("inbox") == "archive" {
+			testutil.JSONResponse(w, http.StatusBadRequest, `{"message":"boom"}`)
+			return
+		}
+		testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"`+r.URL.Query().Get("inbox")+`"}],"hasMore":false}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	scan, err := client.Chats().Enumerate(context.Background(), EnumerateParams{})
+	if err != nil {
+		t.Fatalf("Enumerate() error: %v", err)
+	}
+
+	if scan.Complete() {
+		t.Error("scan should be incomplete")
+	}
+	if len(scan.Chats) != 2 {
+		t.Errorf("len(Chats) = %d, want 2", len(scan.Chats))
+	}
+	if !strings.Contains(scan.Summary(), "archive stopped after 0 pages") {
+		t.Errorf("Summary() = %q, want mention of the archive inbox", scan.Summary())
+	}
+}
+
+func TestEnumerateFailsWhenNothingReadable(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	if _, err := client.Chats().Enumerate(context.Background(), EnumerateParams{Inboxes: []string{"primary"}}); err == nil {
+		t.Error("Enumerate() should fail when no inbox can be read")
+	}
+}
+
+func TestEnumerateRecordsRepeatedCursor(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"1"}],"hasMore":true,"cursor":"same"}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	scan, err := client.Chats().Enumerate(context.Background(), EnumerateParams{Inboxes: []string{"primary"}})
+	if err != nil {
+		t.Fatalf("Enumerate() error: %v", err)
+	}
+	if in := scan.Inboxes[0]; in.Complete || in.Error != "pagination cursor repeated" || in.Pages != 2 {
+		t.Errorf("inbox = %+v, want incomplete after 2 pages with a repeated cursor", in)
+	}
+}
@@ -76,17 +76,13 @@ func ParseError(resp *http.Response) error {
 }
 
 func IsNotFound(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
-		return apiErr.StatusCode == http.StatusNotFound
-	}
-	return false
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
 }
 
 func IsUnauthorized(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
-		return apiErr.StatusCode == http.StatusUnauthorized
-	}
-	return false
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
 }
 
 // UserFriendlyError wraps an error with a user-friendly message
@@ -132,26 +128,34 @@ func ParseErrorWithContext(resp *http.Response, context string) error {
 	// Handle specific status codes with friendly messages
 	switch resp.StatusCode {
 	case http.StatusUnauthorized:
-		return fmt.Errorf("Invalid or expired token. Run: beeper auth add") //nolint:staticcheck // User-facing error message
+		return &statusError{"Invalid or expired token. Run: beeper auth add", apiErr}
 	case http.StatusNotFound:
 		if context != "" {
-			return fmt.Errorf("%s not found", context)
+			return &statusError{context + " not found", apiErr}
 		}
 		if apiErr.Message != "" {
-			return fmt.Errorf("Not found: %s", apiErr.Message) //nolint:staticcheck // User-facing error message
+			return &statusError{"Not found: " + apiErr.Message, apiErr}
 		}
-		return fmt.Errorf("Not found") //nolint:staticcheck // User-facing error message
+		return &statusError{"Not found", apiErr}
 	case http.StatusBadRequest, http.StatusInternalServerError:
 		// Validation errors typically come as 400 or 500
 		if apiErr.Message != "" {
-			return fmt.Errorf("invalid request: %s", apiErr.Message)
+			return &statusError{"invalid request: " + apiErr.Message, apiErr}
 		}
 		return apiErr
 	default:
 		// Show Beeper's error message directly for other API errors
-		if apiErr.Message != "" {
-			return fmt.Errorf("%s", apiErr.Message)
-		}
 		return apiErr
 	}
 }
+
+// statusError is a friendly message for an API error response. It unwraps
+// to the *APIError, so errors.As still finds the status code.
+type statusError struct {
+	msg string
+	err *APIError
+}
+
+func (e *statusError) Error() string { return e.msg }
+
+func (e *statusError) Unwrap() error { return e.err }
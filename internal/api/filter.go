@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"iter"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var linkRe = regexp.MustCompile(`(?i)\bhttps?://\S|\bwww\.\S+\.\S`)
+
+// MessageFilter narrows search hits on the client, for criteria the search
+// API doesn't accept. The zero value matches everything.
+type MessageFilter struct {
+	Before     time.Time // exclusive
+	Sender     string    // case-insensitive substring of the sender's name or ID
+	FromMe     bool
+	FromOthers bool
+	HasLink    bool
+	HasMedia   bool
+}
+
+// Local names the criteria f applies, in flag order, so callers can say
+// which filters ran on the client.
+func (f MessageFilter) Local() []string {
+	var names []string
+	for _, c := range []struct {
+		on   bool
+		name string
+	}{
+		{!f.Before.IsZero(), "before"},
+		{f.Sender != "", "sender"},
+		{f.FromMe, "from-me"},
+		{f.FromOthers, "from-others"},
+		{f.HasLink, "has-link"},
+		{f.HasMedia, "has-media"},
+	} {
+		if c.on {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// Match reports whether m, sent in chat (which may be nil), passes f.
+func (f MessageFilter) Match(m Message, chat *Chat) bool {
+	if !f.Before.IsZero() && !m.Timestamp.Before(f.Before) {
+		return false
+	}
+	if f.FromMe || f.FromOthers {
+		if IsFromMe(m, chat) != f.FromMe {
+			return false
+		}
+	}
+	if f.Sender != "" {
+		want := strings.ToLower(f.Sender)
+		if !containsFold([]string{SenderName(m, chat), m.Sender, m.SenderID}, want) {
+			return false
+		}
+	}
+	if f.HasLink && !linkRe.MatchString(m.Text) {
+		return false
+	}
+	if f.HasMedia && len(m.Attachments) == 0 {
+		return false
+	}
+	return true
+}
+
+// Apply yields the hits that pass f, stopping after max matches or after
+// reading maxScan hits (0 means no limit for either). Senders are named with
+// the chats' participants, fetched through senders when a criterion needs
+// them. scanned, if not nil, is set to the number of hits read.
+func (f MessageFilter) Apply(ctx context.Context, hits iter.Seq2[SearchHit, error], senders *Senders, max, maxScan int, scanned *int) iter.Seq2[SearchHit, error] {
+	needChat := f.Sender != "" || f.FromMe || f.FromOthers
+	return func(yield func(SearchHit, error) bool) {
+		read, matched := 0, 0
+		defer func() {
+			if scanned != nil {
+				*scanned = read
+			}
+		}()
+		for hit, err := range hits {
+			if err != nil {
+				yield(hit, err)
+				return
+			}
+			read++
+			chat := hit.Chat
+			if needChat && senders != nil {
+				senders.Add(chat)
+				if full := senders.chat(ctx, hit.ChatID); full != nil {
+					chat = full
+				}
+			}
+			if f.Match(hit.Message, chat) {
+				if !yield(hit, nil) {
+					return
+				}
+				matched++
+				if max > 0 && matched >= max {
+					return
+				}
+			}
+			if maxScan > 0 && read >= maxScan {
+				return
+			}
+		}
+	}
+}
+
+func containsFold(values []string, sub string) bool {
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), sub) {
+			return true
+		}
+	}
+	return false
+}
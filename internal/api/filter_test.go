@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMessageFilterMatch(t *testing.T) {
+	group := groupChat()
+	noon := time.Date(2025, time.March, 5, 12, 0, 0, 0, time.UTC)
+	mine := Message{SenderID: "@me:beeper.com", Text: "see https://example.com", Timestamp: noon}
+	ann := Message{SenderID: "@ann:slack", Text: "photo", Timestamp: noon.Add(time.Hour), Attachments: []Attachment{{Type: "img"}}}
+	namedYou := Message{SenderID: "@you:slack", Sender: "You", Text: "hi", Timestamp: noon}
+
+	tests := []struct {
+		name   string
+		filter MessageFilter
+		m      Message
+		want   bool
+	}{
+		{"zero value", MessageFilter{}, ann, true},
+		{"before excludes the instant", MessageFilter{Before: noon}, mine, false},
+		{"before", MessageFilter{Before: noon.Add(time.Minute)}, mine, true},
+		{"from me via self participant", MessageFilter{FromMe: true}, mine, true},
+		{"from me", MessageFilter{FromMe: true}, ann, false},
+		{"from others", MessageFilter{FromOthers: true}, ann, true},
+		{"from others excludes me", MessageFilter{FromOthers: true}, mine, false},
+		{"contact named You is not me", MessageFilter{FromMe: true}, namedYou, false},
+		{"contact named You is someone else", MessageFilter{FromOthers: true}, namedYou, true},
+		{"from me via IsMe", MessageFilter{FromMe: true}, Message{IsMe: true, Timestamp: noon}, true},
+		{"sender by participant name", MessageFilter{Sender: "ann lee"}, ann, true},
+		{"sender by ID", MessageFilter{Sender: "@ann:"}, ann, true},
+		{"sender mismatch", MessageFilter{Sender: "bob"}, ann, false},
+		{"has link", MessageFilter{HasLink: true}, mine, true},
+		{"no link", MessageFilter{HasLink: true}, ann, false},
+		{"has media", MessageFilter{HasMedia: true}, ann, true},
+		{"no media", MessageFilter{HasMedia: true}, mine, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(tt.m, group); got != tt.want {
+			t.Errorf("%s: Match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMessageFilterLocal(t *testing.T) {
+	f := MessageFilter{Before: time.Now(), FromOthers: true, HasMedia: true}
+	got := f.Local()
+	want := []string{"before", "from-others", "has-media"}
+	if len(got) != len(want) {
+		t.Fatalf("Local() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Local() = %v, want %v", got, want)
+		}
+	}
+	if (MessageFilter{}).Local() != nil {
+		t.Error("zero filter reports local criteria")
+	}
+}
+
+func TestMessageFilterApply(t *testing.T) {
+	group := groupChat()
+	var hits []SearchHit
+	for i, id := range []string{"@ann:slack", "@bob:slack", "@ann:slack", "@bob:slack", "@ann:slack"} {
+		hits = append(hits, SearchHit{Message: Message{ID: string(rune('a' + i)), ChatID: group.ID, SenderID: id}, Chat: group})
+	}
+	seq := func(yield func(SearchHit, error) bool) {
+		for _, h := range hits {
+			if !yield(h, nil) {
+				return
+			}
+		}
+	}
+	collect := func(max, maxScan int) (ids string, scanned int) {
+		f := MessageFilter{Sender: "ann"}
+		for hit, err := range f.Apply(context.Background(), seq, NewSenders(nil), max, maxScan, &scanned) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids += hit.ID
+		}
+		return ids, scanned
+	}
+
+	if ids, scanned := collect(0, 0); ids != "ace" || scanned != 5 {
+		t.Errorf("unbounded: got %q after %d, want ace after 5", ids, scanned)
+	}
+	if ids, scanned := collect(2, 0); ids != "ac" || scanned != 3 {
+		t.Errorf("max 2: got %q after %d, want ac after 3", ids, scanned)
+	}
+	if ids, scanned := collect(0, 2); ids != "a" || scanned != 2 {
+		t.Errorf("maxScan 2: got %q after %d, want a after 2", ids, scanned)
+	}
+}
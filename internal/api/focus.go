@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FocusChat defaults.
+const (
+	DefaultFocusTimeout  = 5 * time.Second
+	DefaultFocusRetries  = 3
+	DefaultFocusInterval = 250 * time.Millisecond
+	DefaultFocusSettle   = 500 * time.Millisecond
+)
+
+// FocusResponse is Beeper's answer to a focus request.
+type FocusResponse struct {
+	Success *bool `json:"success,omitempty"`
+	// ChatID is the chat Beeper reports as open, when it reports one.
+	ChatID string `json:"chatID,omitempty"`
+}
+
+// check reports whether the response shows chatID open. A response that
+// neither rejects the request nor names the open chat is accepted but
+// unconfirmed.
+func (r FocusResponse) check(chatID string) (confirmed bool, err error) {
+	if r.Success != nil && !*r.Success {
+		return false, errors.New("focus request was not accepted")
+	}
+	if r.ChatID == "" || chatID == "" {
+		return false, nil
+	}
+	if r.ChatID != chatID {
+		return false, fmt.Errorf("chat %s is open instead", r.ChatID)
+	}
+	return true, nil
+}
+
+// FocusOptions bound how long FocusChat waits for Beeper to open a chat.
+type FocusOptions struct {
+	// Timeout bounds navigation and the settle wait together.
+	Timeout time.Duration
+	// Retries is how many more times navigation is attempted after it fails
+	// or another chat is reported open.
+	Retries int
+	// Interval is the pause before retrying failed navigation.
+	Interval time.Duration
+	// Settle is how long to wait after Beeper accepts navigation without
+	// naming the open chat. This is a timed wait, not a confirmation:
+	// navigation is repeated and the draft is set if Beeper accepts it again.
+	Settle time.Duration
+}
+
+// FocusError reports that navigation to a chat failed or ran out of time, so
+// no draft was applied.
+type FocusError struct {
+	ChatID   string
+	Attempts int
+	Timeout  time.Duration
+	Err      error
+}
+
+func (e *FocusError) Error() string {
+	return fmt.Sprintf("failed to open chat %s (%d attempts within %s): %v; the draft was not set",
+		e.ChatID, e.Attempts, e.Timeout, e.Err)
+}
+
+func (e *FocusError) Unwrap() error {
+	return e.Err
+}
+
+// Focus brings Beeper Desktop to the foreground, optionally opening a chat
+// and pre-filling a draft.
+func (c *Client) Focus(ctx context.Context, req FocusRequest) error {
+	resp, err := c.focus(ctx, req)
+	if err != nil {
+		return err
+	}
+	_, err = resp.check(req.ChatID)
+	return err
+}
+
+// FocusChat navigates to req.ChatID before applying req's draft. If Beeper
+// names the open chat in its response, the draft waits until it names
+// req.ChatID. Beeper's focus endpoint usually names no chat, and then the
+// switch cannot be confirmed: FocusChat waits opts.Settle, repeats
+// navigation, and sets the draft once that is accepted. Failed navigation
+// is retried every opts.Interval, up to opts.Retries more times; a
+// *FocusError is returned if navigation does not succeed within
+// opts.Timeout.
+func (c *Client) FocusChat(ctx context.Context, req FocusRequest, opts FocusOptions) error {
+	if req.ChatID == "" {
+		return errors.New("no chat to focus")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultFocusTimeout
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultFocusInterval
+	}
+	if opts.Settle <= 0 {
+		opts.Settle = DefaultFocusSettle
+	}
+	opts.Retries = max(opts.Retries, 0)
+
+	navCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	nav := FocusRequest{ChatID: req.ChatID, MessageID: req.MessageID}
+	attempts, failures := 0, 0
+	settled := false
+	for {
+		attempts++
+		resp, err := c.focus(navCtx, nav)
+		confirmed := false
+		if err == nil {
+			confirmed, err = resp.check(req.ChatID)
+		}
+		if confirmed || err == nil && settled {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		wait := opts.Settle
+		if err != nil {
+			failures++
+			settled = false
+			if failures > opts.Retries || navCtx.Err() != nil {
+				return &FocusError{ChatID: req.ChatID, Attempts: attempts, Timeout: opts.Timeout, Err: err}
+			}
+			wait = opts.Interval
+		} else {
+			settled = true
+			err = errors.New("timed out during the settle wait")
+		}
+		select {
+		case <-navCtx.Done():
+			return &FocusError{ChatID: req.ChatID, Attempts: attempts, Timeout: opts.Timeout, Err: err}
+		case <-time.After(wait):
+		}
+	}
+
+	if req.DraftText == "" && req.DraftAttachmentPath == "" {
+		return nil
+	}
+	draft := FocusRequest{
+		ChatID:              req.ChatID,
+		DraftText:           req.DraftText,
+		DraftAttachmentPath: req.DraftAttachmentPath,
+	}
+	if err := c.Focus(ctx, draft); err != nil {
+		return fmt.Errorf("failed to set draft: %w", err)
+	}
+	return nil
+}
+
+// focus posts one focus request. An empty response body is not an error.
+func (c *Client) focus(ctx context.Context, req FocusRequest) (FocusResponse, error) {
+	var out FocusResponse
+	resp, err := c.Post(ctx, "/v1/focus", req)
+	if err != nil {
+		return out, UserFriendlyError(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := ParseErrorWithContext(resp, ""); err != nil {
+		return out, err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil && !errors.Is(err, io.EOF) {
+		return out, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+// focusServer answers focus requests with the given bodies in turn, repeating
+// the last, and records the requests.
+func focusServer(t *testing.T, bodies ...string) (*Client, func() []FocusRequest) {
+	t.Helper()
+	var (
+		mu   sync.Mutex
+		reqs []FocusRequest
+	)
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body FocusRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		n := len(reqs)
+		reqs = append(reqs, body)
+		mu.Unlock()
+		testutil.JSONResponse(w, http.StatusOK, bodies[min(n, len(bodies)-1)])
+	})
+	return NewClient(server.URL, "test-token"), func() []FocusRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]FocusRequest(nil), reqs...)
+	}
+}
+
+func TestFocusChatWaitsForConfirmation(t *testing.T) {
+	client, requests := focusServer(t,
+		`{"success":false}`,
+		`{"success":true,"chatID":"!old"}`,
+		`{"success":true,"chatID":"!a"}`,
+		`{"success":true}`,
+	)
+	req := FocusRequest{ChatID: "!a", MessageID: "m1", DraftText: "hi"}
+	if err := client.FocusChat(context.Background(), req, FocusOptions{Retries: 3, Interval: time.Millisecond}); err != nil {
+		t.Fatalf("FocusChat() error: %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 4 {
+		t.Fatalf("got %d requests, want 3 navigations and a draft: %+v", len(reqs), reqs)
+	}
+	for _, r := range reqs[:3] {
+		if r.ChatID != "!a" || r.MessageID != "m1" || r.DraftText != "" {
+			t.Errorf("navigation request = %+v, want chat and message only", r)
+		}
+	}
+	if d := reqs[3]; d.ChatID != "!a" || d.DraftText != "hi" || d.MessageID != "" {
+		t.Errorf("draft request = %+v", d)
+	}
+}
+
+func TestFocusChatGivesUp(t *testing.T) {
+	client, requests := focusServer(t, `{"success":true,"chatID":"!old"}`)
+	err := client.FocusChat(context.Background(), FocusRequest{ChatID: "!a", DraftText: "hi"},
+		FocusOptions{Retries: 2, Interval: time.Millisecond})
+
+	var focusErr *FocusError
+	if !errors.As(err, &focusErr) {
+		t.Fatalf("FocusChat() error = %v, want a *FocusError", err)
+	}
+	if focusErr.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", focusErr.Attempts)
+	}
+	for _, r := range requests() {
+		if r.DraftText != "" {
+			t.Error("draft was sent although the chat never opened")
+		}
+	}
+}
+
+func TestFocusChatTimeout(t *testing.T) {
+	client, requests := focusServer(t, `{"success":false}`)
+	start := time.Now()
+	err := client.FocusChat(context.Background(), FocusRequest{ChatID: "!a", DraftText: "hi"},
+		FocusOptions{Timeout: 50 * time.Millisecond, Retries: 1000, Interval: 10 * time.Millisecond})
+
+	var focusErr *FocusError
+	if !errors.As(err, &focusErr) {
+		t.Fatalf("FocusChat() error = %v, want a *FocusError", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("FocusChat() took %v, past its 50ms timeout", elapsed)
+	}
+	if n := len(requests()); n < 2 || n > 10 {
+		t.Errorf("got %d attempts in 50ms at 10ms intervals", n)
+	}
+}
+
+func TestFocusChatSettlesWhenChatIsNotReported(t *testing.T) {
+	for _, body := range []string{``, `{"success":true}`} {
+		client, requests := focusServer(t, body)
+		start := time.Now()
+		err := client.FocusChat(context.Background(), FocusRequest{ChatID: "!a", DraftText: "hi"},
+			FocusOptions{Settle: 30 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("FocusChat() with response %q error: %v", body, err)
+		}
+		if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+			t.Errorf("draft set after %v, before the chat could settle", elapsed)
+		}
+		reqs := requests()
+		if len(reqs) != 3 || reqs[0].DraftText != "" || reqs[1].DraftText != "" || reqs[2].DraftText != "hi" {
+			t.Errorf("requests = %+v, want two navigations and then the draft", reqs)
+		}
+	}
+}
+
+func TestFocusChatUnreportedTimesOut(t *testing.T) {
+	client, requests := focusServer(t, `{"success":true}`)
+	err := client.FocusChat(context.Background(), FocusRequest{ChatID: "!a", DraftText: "hi"},
+		FocusOptions{Timeout: 20 * time.Millisecond, Settle: time.Second})
+
+	var focusErr *FocusError
+	if !errors.As(err, &focusErr) {
+		t.Fatalf("FocusChat() error = %v, want a *FocusError", err)
+	}
+	for _, r := range requests() {
+		if r.DraftText != "" {
+			t.Error("draft was sent although the chat was never confirmed")
+		}
+	}
+}
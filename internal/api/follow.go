@@ -0,0 +1,402 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// EventsPath is the server-sent events stream Follow uses when Beeper
+// Desktop provides one.
+const EventsPath = "/v1/events"
+
+// Follow polling defaults.
+const (
+	DefaultFollowMinInterval = time.Second
+	DefaultFollowMaxInterval = 15 * time.Second
+
+	// followMaxPages bounds how many pages one chat is read per poll.
+	followMaxPages = 5
+	// followSeenLimit bounds the message IDs kept for de-duplication.
+	followSeenLimit = 10000
+)
+
+// ErrPushUnavailable reports that Beeper Desktop has no event stream.
+var ErrPushUnavailable = errors.New("event stream not available")
+
+// FollowParams selects what Follow watches.
+type FollowParams struct {
+	// ChatIDs are the chats to follow; empty follows every chat.
+	ChatIDs    []string
+	AccountIDs []string
+	// MinInterval and MaxInterval bound the polling interval, which backs
+	// off while nothing arrives and resets when a message does.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// NoPush disables the event stream and always polls.
+	NoPush bool
+	// Since also reports messages that arrived after this time but before
+	// Follow started (zero means only new messages). Catch-up is limited to
+	// each chat's latest page.
+	Since time.Time
+}
+
+// MessageEvent is a newly arrived message and the chat it belongs to.
+type MessageEvent struct {
+	Message
+	Chat *Chat `json:"chat,omitempty"`
+}
+
+// Follow yields messages that arrive after it starts, oldest first, until
+// ctx is cancelled or the consumer stops. It reads the event stream when
+// available and otherwise polls with direction=after cursors. Errors are
+// yielded without ending the iteration, so callers can report them and
+// keep following; a consumer that wants to stop on error returns false.
+func (s *MessagesService) Follow(ctx context.Context, params FollowParams) iter.Seq2[MessageEvent, error] {
+	return func(yield func(MessageEvent, error) bool) {
+		f := newFollower(s.client, params)
+		if err := f.loadChats(ctx); err != nil {
+			yield(MessageEvent{}, err)
+			return
+		}
+
+		if !params.NoPush {
+			if !params.Since.IsZero() {
+				// The stream only carries new events; poll once for the gap.
+				if _, ok := f.pollOnce(ctx, yield); !ok {
+					return
+				}
+			}
+			err := f.stream(ctx, yield)
+			if err == nil || ctx.Err() != nil {
+				return
+			}
+			if !errors.Is(err, ErrPushUnavailable) {
+				// The stream dropped: poll from here on, and report why.
+				if !yield(MessageEvent{}, fmt.Errorf("event stream ended, falling back to polling: %w", err)) {
+					return
+				}
+			}
+		}
+
+		f.poll(ctx, yield)
+	}
+}
+
+type followedChat struct {
+	chat     Chat
+	cursor   string    // sort key of the newest message seen
+	activity time.Time // LastActivity when last polled
+}
+
+type follower struct {
+	client *Client
+	params FollowParams
+	since  time.Time
+	chats  map[string]*followedChat
+	order  []string
+	seen   map[string]bool
+	seenQ  []string
+}
+
+func newFollower(client *Client, params FollowParams) *follower {
+	if params.MinInterval <= 0 {
+		params.MinInterval = DefaultFollowMinInterval
+	}
+	if params.MaxInterval < params.MinInterval {
+		params.MaxInterval = max(DefaultFollowMaxInterval, params.MinInterval)
+	}
+	since := time.Now()
+	if !params.Since.IsZero() && params.Since.Before(since) {
+		since = params.Since
+	}
+	return &follower{
+		client: client,
+		params: params,
+		since:  since,
+		chats:  map[string]*followedChat{},
+		seen:   map[string]bool{},
+	}
+}
+
+// loadChats looks up the followed chats, or takes a baseline of recent
+// activity when following everything.
+func (f *follower) loadChats(ctx context.Context) error {
+	if len(f.params.ChatIDs) > 0 {
+		for _, id := range f.params.ChatIDs {
+			chat, err := f.client.Chats().Get(ctx, id)
+			if err != nil {
+				return err
+			}
+			f.track(*chat)
+		}
+		return nil
+	}
+
+	result, err := f.client.Chats().List(ctx, ListChatsParams{AccountIDs: f.params.AccountIDs})
+	if err != nil {
+		return err
+	}
+	for _, chat := range result.Items {
+		// Chats active since f.since are checked on the first poll.
+		f.track(chat).activity = minTime(chat.LastActivity, f.since)
+	}
+	return nil
+}
+
+func (f *follower) track(chat Chat) *followedChat {
+	fc, ok := f.chats[chat.ID]
+	if !ok {
+		fc = &followedChat{}
+		f.chats[chat.ID] = fc
+		f.order = append(f.order, chat.ID)
+	}
+	fc.chat = chat
+	return fc
+}
+
+// markSeen records id and reports whether it was new.
+func (f *follower) markSeen(id string) bool {
+	if f.seen[id] {
+		return false
+	}
+	f.seen[id] = true
+	f.seenQ = append(f.seenQ, id)
+	if len(f.seenQ) > followSeenLimit {
+		delete(f.seen, f.seenQ[0])
+		f.seenQ = f.seenQ[1:]
+	}
+	return true
+}
+
+// poll checks for new messages until ctx is done, backing off while idle.
+func (f *follower) poll(ctx context.Context, yield func(MessageEvent, error) bool) {
+	interval := f.params.MinInterval
+	for {
+		found, ok := f.pollOnce(ctx, yield)
+		if !ok || ctx.Err() != nil {
+			return
+		}
+		if found > 0 {
+			interval = f.params.MinInterval
+		} else {
+			interval = min(interval*3/2, f.params.MaxInterval)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// pollOnce checks each chat that may have new messages. It returns how many
+// messages were yielded, and false once the consumer has stopped.
+func (f *follower) pollOnce(ctx context.Context, yield func(MessageEvent, error) bool) (int, bool) {
+	var due []*followedChat
+	if len(f.params.ChatIDs) > 0 {
+		for _, id := range f.order {
+			due = append(due, f.chats[id])
+		}
+	} else {
+		result, err := f.client.Chats().List(ctx, ListChatsParams{AccountIDs: f.params.AccountIDs})
+		if err != nil {
+			return 0, ctx.Err() == nil && yield(MessageEvent{}, err)
+		}
+		for _, chat := range result.Items {
+			_, known := f.chats[chat.ID]
+			fc := f.track(chat)
+			if chat.LastActivity.After(fc.activity) && (known || chat.LastActivity.After(f.since)) {
+				due = append(due, fc)
+			}
+		}
+	}
+
+	found := 0
+	for _, fc := range due {
+		messages, err := f.fetchNew(ctx, fc)
+		if err != nil {
+			if ctx.Err() != nil || !yield(MessageEvent{}, err) {
+				return found, false
+			}
+			continue
+		}
+		fc.activity = fc.chat.LastActivity
+		for _, m := range messages {
+			found++
+			chat := fc.chat
+			if !yield(MessageEvent{Message: m, Chat: &chat}, nil) {
+				return found, false
+			}
+		}
+	}
+	return found, true
+}
+
+// fetchNew returns a chat's unseen messages newer than the follow start,
+// oldest first, and advances its cursor.
+func (f *follower) fetchNew(ctx context.Context, fc *followedChat) ([]Message, error) {
+	var (
+		items  []Message
+		cursor = fc.cursor
+	)
+	for range followMaxPages {
+		params := ListMessagesParams{}
+		if cursor != "" {
+			params = ListMessagesParams{Cursor: cursor, Direction: "after"}
+		}
+		page, err := f.client.Messages().List(ctx, fc.chat.ID, params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		// Without a cursor we read the latest page, which is all we need.
+		if cursor == "" || !page.HasMore || page.Cursor == "" || page.Cursor == cursor {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	slices.SortStableFunc(items, func(a, b Message) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	var fresh []Message
+	for _, m := range items {
+		if m.SortKey != "" {
+			fc.cursor = m.SortKey
+		}
+		if m.Timestamp.Before(f.since) || !f.markSeen(m.ID) {
+			continue
+		}
+		fresh = append(fresh, m)
+	}
+	return fresh, nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// wants reports whether a pushed message belongs to the followed chats.
+func (f *follower) wants(m *Message) bool {
+	if len(f.params.ChatIDs) > 0 {
+		return slices.Contains(f.params.ChatIDs, m.ChatID)
+	}
+	return len(f.params.AccountIDs) == 0 || slices.Contains(f.params.AccountIDs, m.AccountID)
+}
+
+// streamEvent is one server-sent event payload: a message, optionally
+// wrapped with its chat.
+type streamEvent struct {
+	Type    string   `json:"type"`
+	Message *Message `json:"message"`
+	Chat    *Chat    `json:"chat"`
+}
+
+// stream reads the event stream until it ends. It returns
+// ErrPushUnavailable when the server has none.
+func (f *follower) stream(ctx context.Context, yield func(MessageEvent, error) bool) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.client.baseURL+EventsPath, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+f.client.token)
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream stays open indefinitely, so skip the client's timeout.
+	httpClient := &http.Client{Transport: f.client.httpClient.Transport}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return ErrPushUnavailable
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return ErrPushUnavailable
+	}
+
+	var (
+		event string
+		data  strings.Builder
+	)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 && (event == "" || strings.HasPrefix(event, "message")) {
+				if !f.dispatch(ctx, data.String(), yield) {
+					return nil
+				}
+			}
+			event = ""
+			data.Reset()
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("server closed the stream")
+}
+
+// dispatch decodes one event and yields it if it is a new, followed
+// message. It returns false once the consumer has stopped.
+func (f *follower) dispatch(ctx context.Context, data string, yield func(MessageEvent, error) bool) bool {
+	var ev streamEvent
+	if err := json.Unmarshal([]byte(data), &ev); err != nil {
+		return true
+	}
+	if ev.Type != "" && !strings.HasPrefix(ev.Type, "message") {
+		return true
+	}
+	if ev.Message == nil {
+		// A bare message rather than a wrapped event.
+		var m Message
+		if err := json.Unmarshal([]byte(data), &m); err != nil || m.ID == "" {
+			return true
+		}
+		ev.Message = &m
+	}
+
+	m := ev.Message
+	if m.ID == "" || !f.wants(m) || !f.markSeen(m.ID) {
+		return true
+	}
+
+	chat := ev.Chat
+	if fc, ok := f.chats[m.ChatID]; ok && chat == nil {
+		c := fc.chat
+		chat = &c
+	} else if chat == nil {
+		if c, err := f.client.Chats().Get(ctx, m.ChatID); err == nil {
+			f.track(*c)
+			chat = c
+		}
+	}
+	if m.SortKey != "" {
+		if fc, ok := f.chats[m.ChatID]; ok {
+			fc.cursor = m.SortKey
+		}
+	}
+	return yield(MessageEvent{Message: *m, Chat: chat}, nil)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+func followParams(chatIDs ...string) FollowParams {
+	return FollowParams{ChatIDs: chatIDs, MinInterval: 5 * time.Millisecond, MaxInterval: 20 * time.Millisecond}
+}
+
+func messageJSON(id string, ts time.Time, sortKey string) string {
+	return fmt.Sprintf(`{"id":%q,"chatID":"c1","text":"hi %s","timestamp":%q,"sortKey":%q}`, id, id, ts.Format(time.RFC3339Nano), sortKey)
+}
+
+func TestFollowPollsWithAfterCursor(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	var afterCalls atomic.Int32
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case EventsPath:
+			http.NotFound(w, r)
+		case "/v1/chats/c1":
+			testutil.JSONResponse(w, http.StatusOK, `{"id":"c1","title":"Team"}`)
+		case "/v1/chats/c1/messages":
+			switch {
+			case q.Get("cursor") == "":
+				testutil.JSONResponse(w, http.StatusOK, `{"items":[`+messageJSON("old", old, "k1")+`]}`)
+			case q.Get("cursor") == "k1" && q.Get("direction") == "after":
+				// Nothing new on the first poll, then a message arrives.
+				if afterCalls.Add(1) == 1 {
+					testutil.JSONResponse(w, http.StatusOK, `{"items":[]}`)
+					return
+				}
+				testutil.JSONResponse(w, http.StatusOK, `{"items":[`+messageJSON("new", time.Now(), "k2")+`]}`)
+			default:
+				testutil.JSONResponse(w, http.StatusOK, `{"items":[]}`)
+			}
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(server.URL, "test-token")
+	for ev, err := range client.Messages().Follow(ctx, followParams("c1")) {
+		if err != nil {
+			t.Fatalf("Follow() error: %v", err)
+		}
+		if ev.ID != "new" {
+			t.Errorf("got message %q, want only messages newer than the start", ev.ID)
+		}
+		if ev.Chat == nil || ev.Chat.Title != "Team" {
+			t.Errorf("Chat = %+v, want chat details", ev.Chat)
+		}
+		break
+	}
+}
+
+func TestFollowAllChatsWatchesActivity(t *testing.T) {
+	start := time.Now()
+	var listCalls atomic.Int32
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EventsPath:
+			http.NotFound(w, r)
+		case "/v1/chats":
+			activity := start.Add(-time.Minute)
+			if listCalls.Add(1) > 2 {
+				activity = time.Now()
+			}
+			testutil.JSONResponse(w, http.StatusOK, fmt.Sprintf(`{"items":[{"id":"c1","title":"Team","lastActivity":%q}]}`, activity.Format(time.RFC3339Nano)))
+		case "/v1/chats/c1/messages":
+			testutil.JSONResponse(w, http.StatusOK, `{"items":[`+messageJSON("new", time.Now(), "")+`,`+messageJSON("old", start.Add(-time.Minute), "")+`]}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(server.URL, "test-token")
+	var got []string
+	for ev, err := range client.Messages().Follow(ctx, followParams()) {
+		if err != nil {
+			t.Fatalf("Follow() error: %v", err)
+		}
+		got = append(got, ev.ID)
+		break
+	}
+	if len(got) != 1 || got[0] != "new" {
+		t.Errorf("messages = %v, want [new]", got)
+	}
+}
+
+func TestFollowReadsEventStream(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chats/c1":
+			testutil.JSONResponse(w, http.StatusOK, `{"id":"c1","title":"Team"}`)
+		case EventsPath:
+			if r.Header.Get("Accept") != "text/event-stream" {
+				t.Errorf("Accept = %q", r.Header.Get("Accept"))
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "event: chat.updated\ndata: {\"id\":\"c1\"}\n\n")
+			_, _ = fmt.Fprint(w, "data: {\"type\":\"message.upserted\",\"message\":{\"id\":\"x\",\"chatID\":\"other\"}}\n\n")
+			_, _ = fmt.Fprint(w, "data: {\"type\":\"message.upserted\",\"message\":{\"id\":\"m1\",\"chatID\":\"c1\"}}\n\n")
+			_, _ = fmt.Fprint(w, "data: {\"id\":\"m1\",\"chatID\":\"c1\"}\n\n")
+			_, _ = fmt.Fprint(w, "data: {\"id\":\"m2\",\n")
+			_, _ = fmt.Fprint(w, "data: \"chatID\":\"c1\"}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(server.URL, "test-token")
+	var got []string
+	for ev, err := range client.Messages().Follow(ctx, followParams("c1")) {
+		if err != nil {
+			t.Fatalf("Follow() error: %v", err)
+		}
+		if ev.Chat == nil || ev.Chat.Title != "Team" {
+			t.Errorf("Chat = %+v, want chat details", ev.Chat)
+		}
+		got = append(got, ev.ID)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if fmt.Sprint(got) != "[m1 m2]" {
+		t.Errorf("messages = %v, want [m1 m2] without duplicates", got)
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"net/url"
+)
+
+// MessagesService wraps the message endpoints.
+type MessagesService struct {
+	client *Client
+}
+
+// Messages returns the messages service.
+func (c *Client) Messages() *MessagesService {
+	return &MessagesService{client: c}
+}
+
+// ListMessagesParams pages through a chat's messages.
+type ListMessagesParams struct {
+	Cursor    string
+	Direction string // before or after
+}
+
+// SearchMessagesParams filters a message search.
+type SearchMessagesParams struct {
+	Query      string
+	AccountIDs []string
+	ChatIDs    []string
+	DateAfter  string
+	Cursor     string
+	Direction  string
+}
+
+func (p SearchMessagesParams) values() url.Values {
+	params := url.Values{}
+	params.Set("query", p.Query)
+	addAll(params, "accountIDs", p.AccountIDs)
+	addAll(params, "chatIDs", p.ChatIDs)
+	if p.DateAfter != "" {
+		params.Set("dateAfter", p.DateAfter)
+	}
+	if p.Cursor != "" {
+		params.Set("cursor", p.Cursor)
+	}
+	if p.Direction != "" {
+		params.Set("direction", p.Direction)
+	}
+	return params
+}
+
+// List returns one page of messages in a chat.
+func (s *MessagesService) List(ctx context.Context, chatID string, params ListMessagesParams) (*ListMessagesResponse, error) {
+	values := url.Values{}
+	if params.Cursor != "" {
+		values.Set("cursor", params.Cursor)
+	}
+	if params.Direction != "" {
+		values.Set("direction", params.Direction)
+	}
+
+	var result ListMessagesResponse
+	if err := s.client.getJSON(ctx, chatPath(chatID, "/messages"), values, "Chat", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Search returns one page of messages matching the query.
+func (s *MessagesService) Search(ctx context.Context, params SearchMessagesParams) (*SearchMessagesResponse, error) {
+	var result SearchMessagesResponse
+	if err := s.client.getJSON(ctx, "/v1/messages/search", params.values(), "", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Send posts a message to a chat.
+func (s *MessagesService) Send(ctx context.Context, chatID string, req SendMessageRequest) (*SendMessageResponse, error) {
+	var result SendMessageResponse
+	if err := s.client.postJSON(ctx, chatPath(chatID, "/messages"), req, "Chat", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
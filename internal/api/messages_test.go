@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+func TestMessagesList(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chats/!a/messages" {
+			t.Errorf("Path = %q, want '/v1/chats/!a/messages'", r.URL.Path)
+		}
+		if r.URL.Query().Get("cursor") != "c1" || r.URL.Query().Get("direction") != "before" {
+			t.Errorf("query = %q, want cursor=c1&direction=before", r.URL.RawQuery)
+		}
+		testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"m1","text":"hi"}],"hasMore":false}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	result, err := client.Messages().List(context.Background(), "!a", ListMessagesParams{Cursor: "c1", Direction: "before"})
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(result.Items) != 1 || result.Items[0].Text != "hi" {
+		t.Errorf("Items = %+v, want one message 'hi'", result.Items)
+	}
+}
+
+func TestMessagesSearch(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("query") != "invoice" {
+			t.Errorf("query = %q, want 'invoice'", q.Get("query"))
+		}
+		if q.Get("dateAfter") != "2024-01-01" {
+			t.Errorf("dateAfter = %q, want '2024-01-01'", q.Get("dateAfter"))
+		}
+		if got := q["chatIDs"]; len(got) != 1 || got[0] != "!a" {
+			t.Errorf("chatIDs = %v, want [!a]", got)
+		}
+		testutil.JSONResponse(w, http.StatusOK, `{"messages":[{"id":"m1","chatID":"!a"}],"chats":{"!a":{"id":"!a","title":"Alice"}}}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	result, err := client.Messages().Search(context.Background(), SearchMessagesParams{
+		Query:     "invoice",
+		ChatIDs:   []string{"!a"},
+		DateAfter: "2024-01-01",
+	})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if result.Chats["!a"].Title != "Alice" {
+		t.Errorf("Chats[!a].Title = %q, want 'Alice'", result.Chats["!a"].Title)
+	}
+}
+
+func TestMessagesSend(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %q, want POST", r.Method)
+		}
+		var body SendMessageRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Text != "hello" || body.ReplyToMessageID != "m0" {
+			t.Errorf("body = %+v, want text 'hello' replying to m0", body)
+		}
+		testutil.JSONResponse(w, http.StatusOK, `{"messageID":"m1"}`)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	result, err := client.Messages().Send(context.Background(), "!a", SendMessageRequest{Text: "hello", ReplyToMessageID: "m0"})
+	if err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if result.MessageID != "m1" {
+		t.Errorf("MessageID = %q, want 'm1'", result.MessageID)
+	}
+}
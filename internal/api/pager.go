@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"iter"
+)
+
+// page is one fetched page of results and the cursor for the next one.
+type page[T any] struct {
+	items   []T
+	hasMore bool
+	cursor  string
+}
+
+// paginate follows cursors through fetch, yielding items one at a time until
+// the API reports no more pages, the consumer stops, or max items have been
+// yielded (max <= 0 means no limit). A fetch error is yielded once and ends
+// the iteration.
+func paginate[T any](ctx context.Context, cursor string, max int, fetch func(cursor string) (page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		seen := map[string]bool{}
+		count := 0
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			p, err := fetch(cursor)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range p.items {
+				if !yield(item, nil) {
+					return
+				}
+				count++
+				if max > 0 && count >= max {
+					return
+				}
+			}
+			// Stop on an exhausted listing, or a cursor that would loop forever.
+			if !p.hasMore || p.cursor == "" || seen[p.cursor] {
+				return
+			}
+			seen[p.cursor] = true
+			cursor = p.cursor
+		}
+	}
+}
+
+// All iterates over every chat matching params, following cursors across
+// pages. max caps the number of chats yielded (0 means no limit).
+func (s *ChatsService) All(ctx context.Context, params ListChatsParams, max int) iter.Seq2[Chat, error] {
+	return paginate(ctx, params.Cursor, max, func(cursor string) (page[Chat], error) {
+		params.Cursor = cursor
+		result, err := s.List(ctx, params)
+		if err != nil {
+			return page[Chat]{}, err
+		}
+		return page[Chat]{items: result.Items, hasMore: result.HasMore, cursor: result.Cursor}, nil
+	})
+}
+
+// All iterates over a chat's messages, following cursors across pages.
+// max caps the number of messages yielded (0 means no limit).
+func (s *MessagesService) All(ctx context.Context, chatID string, params ListMessagesParams, max int) iter.Seq2[Message, error] {
+	return paginate(ctx, params.Cursor, max, func(cursor string) (page[Message], error) {
+		params.Cursor = cursor
+		result, err := s.List(ctx, chatID, params)
+		if err != nil {
+			return page[Message]{}, err
+		}
+		return page[Message]{items: result.Items, hasMore: result.HasMore, cursor: result.Cursor}, nil
+	})
+}
+
+// SearchHit is a search result paired with the chat it belongs to, when the
+// API included one.
+type SearchHit struct {
+	Message
+	Chat *Chat `json:"chat,omitempty"`
+}
+
+// SearchAll iterates over every message matching params, following cursors
+// across pages. max caps the number of hits yielded (0 means no limit).
+func (s *MessagesService) SearchAll(ctx context.Context, params SearchMessagesParams, max int) iter.Seq2[SearchHit, error] {
+	return paginate(ctx, params.Cursor, max, func(cursor string) (page[SearchHit], error) {
+		params.Cursor = cursor
+		result, err := s.Search(ctx, params)
+		if err != nil {
+			return page[SearchHit]{}, err
+		}
+		hits := make([]SearchHit, 0, len(result.Messages))
+		for _, m := range result.Messages {
+			hit := SearchHit{Message: m}
+			if chat, ok := result.Chats[m.ChatID]; ok {
+				hit.Chat = &chat
+			}
+			hits = append(hits, hit)
+		}
+		return page[SearchHit]{items: hits, hasMore: result.HasMore, cursor: result.Cursor}, nil
+	})
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+// pagedChatsServer serves three pages of two chats each, keyed by cursor.
+func pagedChatsServer(t *testing.T, requests *atomic.Int32) string {
+	t.Helper()
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"1"},{"id":"2"}],"hasMore":true,"cursor":"p2"}`)
+		case "p2":
+			testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"3"},{"id":"4"}],"hasMore":true,"cursor":"p3"}`)
+		case "p3":
+			testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"5"},{"id":"6"}],"hasMore":false}`)
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	})
+	return server.URL
+}
+
+func TestChatsAllFollowsCursors(t *testing.T) {
+	var requests atomic.Int32
+	client := NewClient(pagedChatsServer(t, &requests), "test-token")
+
+	var ids []string
+	for chat, err := range client.Chats().All(context.Background(), ListChatsParams{}, 0) {
+		if err != nil {
+			t.Fatalf("All() error: %v", err)
+		}
+		ids = append(ids, chat.ID)
+	}
+
+	if fmt.Sprint(ids) != "[1 2 3 4 5 6]" {
+		t.Errorf("ids = %v, want [1 2 3 4 5 6]", ids)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("requests = %d, want 3", requests.Load())
+	}
+}
+
+func TestChatsAllStopsAtMax(t *testing.T) {
+	var requests atomic.Int32
+	client := NewClient(pagedChatsServer(t, &requests), "test-token")
+
+	count := 0
+	for _, err := range client.Chats().All(context.Background(), ListChatsParams{}, 3) {
+		if err != nil {
+			t.Fatalf("All() error: %v", err)
+		}
+		count++
+	}
+
+	if count != 3 {
+		t.Errorf("count = %d, want 3", count)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("requests = %d, want 2 (no fetch past max)", requests.Load())
+	}
+}
+
+func TestPaginateStopsOnRepeatedCursor(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		testutil.JSONResponse(w, http.StatusOK, `{"items":[{"id":"m"}],"hasMore":true,"cursor":"same"}`)
+	})
+	client := NewClient(server.URL, "test-token")
+
+	count := 0
+	for _, err := range client.Messages().All(context.Background(), "!a", ListMessagesParams{}, 0) {
+		if err != nil {
+			t.Fatalf("All() error: %v", err)
+		}
+		count++
+	}
+
+	// First page (no cursor) and one page at "same", then the repeat is detected.
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+}
+
+func TestSearchAllAttachesChats(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		testutil.JSONResponse(w, http.StatusOK, `{"messages":[{"id":"m1","chatID":"!a"},{"id":"m2","chatID":"!b"}],"chats":{"!a":{"id":"!a","title":"Alice"}}}`)
+	})
+	client := NewClient(server.URL, "test-token")
+
+	var hits []SearchHit
+	for hit, err := range client.Messages().SearchAll(context.Background(), SearchMessagesParams{Query: "x"}, 0) {
+		if err != nil {
+			t.Fatalf("SearchAll() error: %v", err)
+		}
+		hits = append(hits, hit)
+	}
+
+	if len(hits) != 2 {
+		t.Fatalf("len(hits) = %d, want 2", len(hits))
+	}
+	if hits[0].Chat == nil || hits[0].Chat.Title != "Alice" {
+		t.Errorf("hits[0].Chat = %+v, want Alice", hits[0].Chat)
+	}
+	if hits[1].Chat != nil {
+		t.Errorf("hits[1].Chat = %+v, want nil", hits[1].Chat)
+	}
+}
+
+func TestPaginateYieldsFetchError(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		testutil.JSONResponse(w, http.StatusBadRequest, `{"message":"bad cursor"}`)
+	})
+	client := NewClient(server.URL, "test-token")
+
+	var gotErr error
+	for _, err := range client.Chats().All(context.Background(), ListChatsParams{}, 0) {
+		gotErr = err
+	}
+	if gotErr == nil {
+		t.Error("expected an error from a failing page")
+	}
+}
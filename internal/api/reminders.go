@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// RemindersService wraps the /v1/chats/{id}/reminders endpoints.
+type RemindersService struct {
+	client *Client
+}
+
+// Reminders returns the reminders service.
+func (c *Client) Reminders() *RemindersService {
+	return &RemindersService{client: c}
+}
+
+// Set schedules a reminder for a chat at the given time.
+func (s *RemindersService) Set(ctx context.Context, chatID string, at time.Time) error {
+	body := ReminderRequest{Reminder: NewReminderTime(at)}
+	return s.client.postJSON(ctx, chatPath(chatID, "/reminders"), body, "Chat", nil)
+}
+
+// Clear removes a chat's reminder.
+func (s *RemindersService) Clear(ctx context.Context, chatID string) error {
+	return s.client.deleteJSON(ctx, chatPath(chatID, "/reminders"), "Chat")
+}
+
+// Reminder is a chat's pending reminder.
+type Reminder struct {
+	ChatID    string    `json:"chatID"`
+	Title     string    `json:"title"`
+	Network   string    `json:"network,omitempty"`
+	AccountID string    `json:"accountID,omitempty"`
+	RemindAt  time.Time `json:"remindAt"`
+	Overdue   bool      `json:"overdue"`
+}
+
+// CollectReminders returns the reminders set on chats, soonest first,
+// marking those due before now as overdue.
+func CollectReminders(chats []Chat, now time.Time) []Reminder {
+	reminders := []Reminder{}
+	for _, c := range chats {
+		if c.ReminderAt == nil || c.ReminderAt.IsZero() {
+			continue
+		}
+		title := c.Title
+		if title == "" {
+			title = c.ID
+		}
+		reminders = append(reminders, Reminder{
+			ChatID:    c.ID,
+			Title:     title,
+			Network:   c.Network,
+			AccountID: c.AccountID,
+			RemindAt:  *c.ReminderAt,
+			Overdue:   c.ReminderAt.Before(now),
+		})
+	}
+	slices.SortStableFunc(reminders, func(a, b Reminder) int { return a.RemindAt.Compare(b.RemindAt) })
+	return reminders
+}
+
+// List enumerates every chat matching params and returns their reminders,
+// soonest first, with the scan so callers can warn when it was incomplete.
+func (s *RemindersService) List(ctx context.Context, params EnumerateParams) ([]Reminder, *ChatScan, error) {
+	scan, err := s.client.Chats().Enumerate(ctx, params)
+	if err != nil {
+		return nil, scan, err
+	}
+	return CollectReminders(scan.Chats, time.Now()), scan, nil
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+func TestRemindersSet(t *testing.T) {
+	at := time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC)
+
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/chats/!a/reminders" {
+			t.Errorf("got %s %s, want POST /v1/chats/!a/reminders", r.Method, r.URL.Path)
+		}
+		var body ReminderRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Reminder.RemindAtMs != at.UnixMilli() {
+			t.Errorf("RemindAtMs = %d, want %d", body.Reminder.RemindAtMs, at.UnixMilli())
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	if err := client.Reminders().Set(context.Background(), "!a", at); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+}
+
+func TestRemindersClear(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Method = %q, want DELETE", r.Method)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := NewClient(server.URL, "test-token")
+	if err := client.Reminders().Clear(context.Background(), "!a"); err != nil {
+		t.Fatalf("Clear() error: %v", err)
+	}
+}
+
+func TestRemindersList(t *testing.T) {
+	soon := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	past := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("inbox") != "primary" {
+			testutil.JSONResponse(w, http.StatusOK, `{"items":[],"hasMore":false}`)
+			return
+		}
+		data, _ := json.Marshal(ListChatsResponse{Items: []Chat{
+			{ID: "!later", Title: "Later", ReminderAt: &soon},
+			{ID: "!none", Title: "No reminder"},
+			{ID: "!late", Network: "signal", ReminderAt: &past},
+		}})
+		testutil.JSONResponse(w, http.StatusOK, string(data))
+	})
+
+	client := NewClient(server.URL, "test-token")
+	reminders, scan, err := client.Reminders().List(context.Background(), EnumerateParams{})
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if !scan.Complete() {
+		t.Errorf("scan incomplete: %s", scan.Summary())
+	}
+	if len(reminders) != 2 {
+		t.Fatalf("got %d reminders, want 2: %+v", len(reminders), reminders)
+	}
+	if r := reminders[0]; r.ChatID != "!late" || r.Title != "!late" || !r.Overdue || !r.RemindAt.Equal(past) {
+		t.Errorf("first reminder = %+v, want the overdue one titled by ID", r)
+	}
+	if r := reminders[1]; r.ChatID != "!later" || r.Overdue {
+		t.Errorf("second reminder = %+v, want !later, not overdue", r)
+	}
+}
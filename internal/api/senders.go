@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// SenderName names m's sender from chat's participants, which may be nil.
+// In order it tries: IsMe or a self participant ("You"), the participant's
+// full name, username or phone number, the name the API sent with the
+// message, the chat title for a DM, and finally the sender ID's local part.
+func SenderName(m Message, chat *Chat) string {
+	if IsFromMe(m, chat) {
+		return "You"
+	}
+	if chat != nil && chat.Participants != nil && m.SenderID != "" {
+		for _, p := range chat.Participants.Items {
+			if p.ID != m.SenderID {
+				continue
+			}
+			if name := participantName(p); name != "" {
+				return name
+			}
+			break
+		}
+	}
+	if m.Sender != "" {
+		return m.Sender
+	}
+	if chat != nil && chat.Type != "group" && chat.Title != "" && m.SenderID != "" {
+		// In a DM, anyone who isn't you is the chat's namesake.
+		return chat.Title
+	}
+	return senderIDName(m.SenderID)
+}
+
+// IsFromMe reports whether m was sent by you: IsMe is set, or its sender is
+// chat's self participant.
+func IsFromMe(m Message, chat *Chat) bool {
+	if m.IsMe {
+		return true
+	}
+	if chat == nil || chat.Participants == nil || m.SenderID == "" {
+		return false
+	}
+	for _, p := range chat.Participants.Items {
+		if p.ID == m.SenderID {
+			return p.IsSelf
+		}
+	}
+	return false
+}
+
+func participantName(p Participant) string {
+	for _, name := range []string{p.FullName, p.Username, p.PhoneNumber} {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// senderIDName shortens a Matrix-style ID like @alice:server to "alice".
+func senderIDName(id string) string {
+	if id == "" {
+		return "Unknown"
+	}
+	if rest, ok := strings.CutPrefix(id, "@"); ok {
+		if local, _, _ := strings.Cut(rest, ":"); local != "" {
+			return local
+		}
+	}
+	return id
+}
+
+// Senders names message senders across chats, fetching each chat's
+// participants at most once. It is safe for concurrent use.
+type Senders struct {
+	chats *ChatsService
+	mu    sync.Mutex
+	cache map[string]*Chat // nil entry: lookup failed, don't retry
+}
+
+// NewSenders returns a resolver that looks chats up through chats. A nil
+// service only uses chats passed to Add or Name.
+func NewSenders(chats *ChatsService) *Senders {
+	return &Senders{chats: chats, cache: map[string]*Chat{}}
+}
+
+// Add caches chat if it carries participants.
+func (s *Senders) Add(chat *Chat) {
+	if chat == nil || chat.Participants == nil {
+		return
+	}
+	s.mu.Lock()
+	s.cache[chat.ID] = chat
+	s.mu.Unlock()
+}
+
+// Name names m's sender. chat, if given, is used when it carries
+// participants; otherwise the chat is looked up once and cached.
+func (s *Senders) Name(ctx context.Context, m Message, chat *Chat) string {
+	s.Add(chat)
+	if full := s.chat(ctx, m.ChatID); full != nil {
+		chat = full
+	}
+	return SenderName(m, chat)
+}
+
+func (s *Senders) chat(ctx context.Context, chatID string) *Chat {
+	if chatID == "" {
+		return nil
+	}
+	s.mu.Lock()
+	chat, ok := s.cache[chatID]
+	s.mu.Unlock()
+	if ok || s.chats == nil {
+		return chat
+	}
+
+	chat, err := s.chats.Get(ctx, chatID)
+	if err != nil {
+		chat = nil
+	}
+	s.mu.Lock()
+	s.cache[chatID] = chat
+	s.mu.Unlock()
+	return chat
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+func groupChat() *Chat {
+	return &Chat{
+		ID:    "!g",
+		Title: "Team",
+		Type:  "group",
+		Participants: &ParticipantList{Items: []Participant{
+			{ID: "@me:beeper.com", FullName: "Me", IsSelf: true},
+			{ID: "@ann:slack", FullName: "Ann Lee"},
+			{ID: "@bob:slack", Username: "bobby"},
+		}},
+	}
+}
+
+func TestSenderName(t *testing.T) {
+	group := groupChat()
+	dm := &Chat{ID: "!d", Title: "Carol", Type: "single"}
+
+	tests := []struct {
+		name string
+		m    Message
+		chat *Chat
+		want string
+	}{
+		{"is me", Message{IsMe: true, SenderID: "@x:y"}, group, "You"},
+		{"self participant", Message{SenderID: "@me:beeper.com"}, group, "You"},
+		{"full name", Message{SenderID: "@ann:slack", Sender: "ann"}, group, "Ann Lee"},
+		{"username", Message{SenderID: "@bob:slack"}, group, "bobby"},
+		{"not a participant", Message{SenderID: "@dan:slack", Sender: "Dan"}, group, "Dan"},
+		{"group never uses the title", Message{SenderID: "@eve:slack"}, group, "eve"},
+		{"dm namesake", Message{SenderID: "@carol:wa"}, dm, "Carol"},
+		{"no chat", Message{SenderID: "@frank:matrix.org"}, nil, "frank"},
+		{"nothing known", Message{}, nil, "Unknown"},
+	}
+	for _, tt := range tests {
+		if got := SenderName(tt.m, tt.chat); got != tt.want {
+			t.Errorf("%s: SenderName() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSendersCachesParticipants(t *testing.T) {
+	var gets atomic.Int32
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gets.Add(1)
+		data, _ := json.Marshal(groupChat())
+		testutil.JSONResponse(w, http.StatusOK, string(data))
+	})
+	s := NewSenders(NewClient(server.URL, "test-token").Chats())
+	ctx := context.Background()
+
+	// An event's chat without participants doesn't stop the lookup.
+	bare := &Chat{ID: "!g", Title: "Team", Type: "group"}
+	for range 3 {
+		if got := s.Name(ctx, Message{ChatID: "!g", SenderID: "@ann:slack"}, bare); got != "Ann Lee" {
+			t.Fatalf("Name() = %q, want Ann Lee", got)
+		}
+	}
+	if gets.Load() != 1 {
+		t.Errorf("fetched the chat %d times, want 1", gets.Load())
+	}
+
+	// A chat passed with participants is used without fetching.
+	other := groupChat()
+	other.ID = "!h"
+	if got := s.Name(ctx, Message{ChatID: "!h", SenderID: "@bob:slack"}, other); got != "bobby" || gets.Load() != 1 {
+		t.Errorf("Name() = %q after %d fetches", got, gets.Load())
+	}
+}
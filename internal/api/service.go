@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// getJSON issues a GET request and decodes a successful JSON response into out.
+// notFound names the resource used in 404 errors (see ParseErrorWithContext).
+func (c *Client) getJSON(ctx context.Context, path string, params url.Values, notFound string, out any) error {
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	resp, err := c.Get(ctx, path)
+	if err != nil {
+		return UserFriendlyError(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return decodeResponse(resp, notFound, out)
+}
+
+// postJSON issues a POST request and decodes a successful JSON response into out.
+// A nil out discards the response body.
+func (c *Client) postJSON(ctx context.Context, path string, body any, notFound string, out any) error {
+	resp, err := c.Post(ctx, path, body)
+	if err != nil {
+		return UserFriendlyError(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return decodeResponse(resp, notFound, out)
+}
+
+// deleteJSON issues a DELETE request and checks the response status.
+func (c *Client) deleteJSON(ctx context.Context, path string, notFound string) error {
+	resp, err := c.Delete(ctx, path)
+	if err != nil {
+		return UserFriendlyError(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return decodeResponse(resp, notFound, nil)
+}
+
+func decodeResponse(resp *http.Response, notFound string, out any) error {
+	if err := ParseErrorWithContext(resp, notFound); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// chatPath builds /v1/chats/{id} with an optional trailing segment.
+func chatPath(chatID, suffix string) string {
+	return "/v1/chats/" + url.PathEscape(chatID) + suffix
+}
+
+// ChatDeeplink returns a link that opens the chat in Beeper Desktop.
+func ChatDeeplink(chatID string) string {
+	return "beeper://chat/" + url.PathEscape(chatID)
+}
+
+// addAll appends every non-empty value under key.
+func addAll(params url.Values, key string, values []string) {
+	for _, v := range values {
+		if v != "" {
+			params.Add(key, v)
+		}
+	}
+}
@@ -76,6 +76,18 @@ type Message struct {
 	Timestamp time.Time `json:"timestamp"`
 	IsMe      bool      `json:"isMe"`
 	ReplyTo   *Message  `json:"replyTo,omitempty"`
+	SortKey   string    `json:"sortKey,omitempty"`
+
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a file attached to a message.
+type Attachment struct {
+	Type     string `json:"type,omitempty"` // img, video, audio or unknown
+	FileName string `json:"fileName,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	FileSize int64  `json:"fileSize,omitempty"`
+	SrcURL   string `json:"srcURL,omitempty"`
 }
 
 type ListMessagesResponse struct {
@@ -93,8 +105,16 @@ type SearchMessagesResponse struct {
 
 // SendMessageRequest for sending a message
 type SendMessageRequest struct {
-	Text             string `json:"text,omitempty"`
-	ReplyToMessageID string `json:"replyToMessageID,omitempty"`
+	Text             string             `json:"text,omitempty"`
+	ReplyToMessageID string             `json:"replyToMessageID,omitempty"`
+	Attachment       *MessageAttachment `json:"attachment,omitempty"`
+}
+
+// MessageAttachment refers to an uploaded asset in a sent message.
+type MessageAttachment struct {
+	UploadID string `json:"uploadID"`
+	FileName string `json:"fileName,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
 }
 
 type SendMessageResponse struct {
@@ -30,21 +30,66 @@ type SetupServer struct {
 	shutdown      chan struct{}
 	pendingResult *SetupResult
 	csrfToken     string
+	newClient     func(token string) (*api.Client, error)
+	baseURL       string
+	profile       string
+	store         secrets.Store
+}
+
+// SetupOption configures a SetupServer
+type SetupOption func(*SetupServer)
+
+// WithClientFactory sets how the server builds API clients to validate tokens.
+// The default talks to api.DefaultBaseURL.
+func WithClientFactory(newClient func(token string) (*api.Client, error)) SetupOption {
+	return func(s *SetupServer) {
+		s.newClient = newClient
+	}
+}
+
+// WithBaseURL sets the API endpoint saved alongside the token.
+func WithBaseURL(baseURL string) SetupOption {
+	return func(s *SetupServer) {
+		s.baseURL = baseURL
+	}
+}
+
+// WithProfile sets the profile name the token is saved under (default "default").
+func WithProfile(name string) SetupOption {
+	return func(s *SetupServer) {
+		s.profile = name
+	}
+}
+
+// WithStore sets the credential store the token is saved to. By default the
+// server opens secrets.NewStore when saving.
+func WithStore(store secrets.Store) SetupOption {
+	return func(s *SetupServer) {
+		s.store = store
+	}
 }
 
 // NewSetupServer creates a new setup server
-func NewSetupServer() (*SetupServer, error) {
+func NewSetupServer(opts ...SetupOption) (*SetupServer, error) {
 	// Generate CSRF token
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
 		return nil, fmt.Errorf("failed to generate CSRF token: %w", err)
 	}
 
-	return &SetupServer{
+	s := &SetupServer{
 		result:    make(chan SetupResult, 1),
 		shutdown:  make(chan struct{}),
 		csrfToken: hex.EncodeToString(tokenBytes),
-	}, nil
+		newClient: func(token string) (*api.Client, error) {
+			return api.NewClient(api.DefaultBaseURL, token), nil
+		},
+		profile: "default",
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 // Start starts the setup server and opens the browser
@@ -161,7 +206,14 @@ func (s *SetupServer) handleValidate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Test the token by making an API call
-	client := api.NewClient(api.DefaultBaseURL, req.Token)
+	client, err := s.newClient(req.Token)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"success": false,
+			"error":   fmt.Sprintf("Invalid API endpoint: %v", err),
+		})
+		return
+	}
 	resp, err := client.Get(r.Context(), "/v1/accounts")
 	if err != nil {
 		writeJSON(w, http.StatusOK, map[string]any{
@@ -212,7 +264,14 @@ func (s *SetupServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate first
-	client := api.NewClient(api.DefaultBaseURL, req.Token)
+	client, err := s.newClient(req.Token)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"success": false,
+			"error":   fmt.Sprintf("Invalid API endpoint: %v", err),
+		})
+		return
+	}
 	resp, err := client.Get(r.Context(), "/v1/accounts")
 	if err != nil {
 		writeJSON(w, http.StatusOK, map[string]any{
@@ -232,17 +291,21 @@ func (s *SetupServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Save to keychain
-	store, err := secrets.NewStore()
-	if err != nil {
-		writeJSON(w, http.StatusOK, map[string]any{
-			"success": false,
-			"error":   fmt.Sprintf("Failed to open keyring: %v", err),
-		})
-		return
+	store := s.store
+	if store == nil {
+		store, err = secrets.NewStore()
+		if err != nil {
+			writeJSON(w, http.StatusOK, map[string]any{
+				"success": false,
+				"error":   fmt.Sprintf("Failed to open keyring: %v", err),
+			})
+			return
+		}
 	}
 
-	if err := store.Set("default", secrets.Credentials{
+	if err := store.Set(s.profile, secrets.Credentials{
 		Token:     req.Token,
+		BaseURL:   s.baseURL,
 		CreatedAt: time.Now(),
 	}); err != nil {
 		writeJSON(w, http.StatusOK, map[string]any{
@@ -254,7 +317,7 @@ func (s *SetupServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 
 	// Store pending result
 	s.pendingResult = &SetupResult{
-		Name: "default",
+		Name: s.profile,
 	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
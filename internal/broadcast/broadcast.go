@@ -0,0 +1,264 @@
+// Package broadcast sends one message to many chats, pacing sends per
+// network and collecting a per-chat report.
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/templates"
+)
+
+// Defaults for Options.
+const (
+	DefaultConcurrency = 4
+	DefaultInterval    = 2 * time.Second
+)
+
+// Result statuses.
+const (
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Sender delivers a message; *api.MessagesService implements it.
+type Sender interface {
+	Send(ctx context.Context, chatID string, req api.SendMessageRequest) (*api.SendMessageResponse, error)
+}
+
+// Message renders the text to send to a chat.
+type Message func(chat api.Chat) (string, error)
+
+// Options controls pacing.
+type Options struct {
+	// Concurrency is how many sends may be in flight at once.
+	Concurrency int
+	// Interval is the minimum gap between two sends on the same network,
+	// so a broadcast doesn't trip a network's spam limits.
+	Interval time.Duration
+	// Progress, if set, is called as each chat finishes.
+	Progress func(Result)
+}
+
+// Result is the outcome for one chat.
+type Result struct {
+	ChatID    string `json:"chatID"`
+	Title     string `json:"title"`
+	Network   string `json:"network,omitempty"`
+	Status    string `json:"status"`
+	MessageID string `json:"messageID,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the outcome of a broadcast, in target order.
+type Report struct {
+	Results []Result `json:"results"`
+	Sent    int      `json:"sent"`
+	Failed  int      `json:"failed"`
+	Skipped int      `json:"skipped"`
+}
+
+// Summary describes the report in one line.
+func (r *Report) Summary() string {
+	s := fmt.Sprintf("Sent to %d of %d chats", r.Sent, len(r.Results))
+	if r.Failed > 0 {
+		s += fmt.Sprintf(", %d failed", r.Failed)
+	}
+	if r.Skipped > 0 {
+		s += fmt.Sprintf(", %d skipped", r.Skipped)
+	}
+	return s
+}
+
+// Run sends msg to every chat. A failure in one chat never stops the others;
+// chats not yet sent when ctx is cancelled are reported as skipped. Chats
+// are queued per network, and each worker takes the network whose next send
+// slot comes first, so a network waiting out its interval does not hold up
+// the others.
+func Run(ctx context.Context, sender Sender, chats []api.Chat, msg Message, opts Options) *Report {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+
+	results := make([]Result, len(chats))
+	var mu sync.Mutex
+	progress := func(r Result) {
+		if opts.Progress != nil {
+			mu.Lock()
+			opts.Progress(r)
+			mu.Unlock()
+		}
+	}
+
+	s := &scheduler{interval: opts.Interval}
+	for i, chat := range chats {
+		r := Result{ChatID: chat.ID, Title: chat.Title, Network: NetworkKey(chat)}
+		text, err := msg(chat)
+		if err != nil {
+			r.Status = StatusFailed
+			r.Error = err.Error()
+			results[i] = r
+			progress(r)
+			continue
+		}
+		r.Text = text
+		results[i] = r
+		s.add(r.Network, i)
+	}
+
+	var wg sync.WaitGroup
+	for range min(opts.Concurrency, max(s.pending, 1)) {
+		wg.Go(func() {
+			for {
+				i, slot, ok := s.next()
+				if !ok {
+					return
+				}
+				results[i] = send(ctx, sender, slot, results[i])
+				progress(results[i])
+			}
+		})
+	}
+	wg.Wait()
+
+	report := &Report{Results: results}
+	for _, r := range results {
+		switch r.Status {
+		case StatusSent:
+			report.Sent++
+		case StatusFailed:
+			report.Failed++
+		default:
+			report.Skipped++
+		}
+	}
+	return report
+}
+
+// send delivers r.Text to r.ChatID at slot.
+func send(ctx context.Context, sender Sender, slot time.Time, r Result) Result {
+	if err := waitUntil(ctx, slot); err != nil {
+		r.Status = StatusSkipped
+		r.Error = "cancelled"
+		return r
+	}
+	resp, err := sender.Send(ctx, r.ChatID, api.SendMessageRequest{Text: r.Text})
+	if err != nil {
+		r.Status = StatusFailed
+		r.Error = err.Error()
+		return r
+	}
+	r.Status = StatusSent
+	r.MessageID = resp.MessageID
+	return r
+}
+
+// NetworkKey is the name sends are paced by: the chat's network, or its
+// account when the network is unknown.
+func NetworkKey(chat api.Chat) string {
+	if chat.Network != "" {
+		return chat.Network
+	}
+	return chat.AccountID
+}
+
+// scheduler queues chats per network and hands out send slots at least
+// interval apart on each network.
+type scheduler struct {
+	interval time.Duration
+	mu       sync.Mutex
+	queues   []*networkQueue // in order of first appearance
+	byName   map[string]*networkQueue
+	pending  int
+}
+
+type networkQueue struct {
+	chats []int // indexes into the target list, in target order
+	next  time.Time
+}
+
+func (s *scheduler) add(network string, i int) {
+	if s.byName == nil {
+		s.byName = make(map[string]*networkQueue)
+	}
+	q := s.byName[network]
+	if q == nil {
+		q = &networkQueue{}
+		s.byName[network] = q
+		s.queues = append(s.queues, q)
+	}
+	q.chats = append(q.chats, i)
+	s.pending++
+}
+
+// next takes the first chat of the network whose next slot is earliest and
+// reserves that slot. ok is false once every queue is empty.
+func (s *scheduler) next() (i int, slot time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pick *networkQueue
+	for _, q := range s.queues {
+		if len(q.chats) > 0 && (pick == nil || q.next.Before(pick.next)) {
+			pick = q
+		}
+	}
+	if pick == nil {
+		return 0, time.Time{}, false
+	}
+	i, pick.chats = pick.chats[0], pick.chats[1:]
+	s.pending--
+
+	slot = pick.next
+	if now := time.Now(); slot.Before(now) {
+		slot = now
+	}
+	if s.interval > 0 {
+		pick.next = slot.Add(s.interval)
+	}
+	return i, slot, true
+}
+
+// waitUntil blocks until t or until ctx is done.
+func waitUntil(ctx context.Context, t time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d := time.Until(t); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// TextMessage returns a Message that sends text as written.
+func TextMessage(text string) Message {
+	return func(api.Chat) (string, error) { return text, nil }
+}
+
+// TextTemplateMessage parses text as a template rendered per chat, e.g.
+// "Hi {{.FirstNames}}".
+func TextTemplateMessage(text string) (Message, error) {
+	tmpl, err := templates.Parse("message", text)
+	if err != nil {
+		return nil, err
+	}
+	return TemplateMessage(tmpl, nil), nil
+}
+
+// TemplateMessage renders tmpl for each chat with vars.
+func TemplateMessage(tmpl *templates.Template, vars map[string]string) Message {
+	return func(chat api.Chat) (string, error) {
+		return tmpl.Render(templates.NewData(&chat, vars, time.Now()))
+	}
+}
@@ -0,0 +1,152 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+type recordingSender struct {
+	mu    sync.Mutex
+	sends map[string][]time.Time // by chat ID
+	fail  map[string]bool
+}
+
+func (s *recordingSender) Send(_ context.Context, chatID string, req api.SendMessageRequest) (*api.SendMessageResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sends == nil {
+		s.sends = make(map[string][]time.Time)
+	}
+	s.sends[chatID] = append(s.sends[chatID], time.Now())
+	if s.fail[chatID] {
+		return nil, errors.New("rate limited")
+	}
+	return &api.SendMessageResponse{MessageID: "m-" + chatID + "-" + req.Text}, nil
+}
+
+func TestRunReportsPerChat(t *testing.T) {
+	chats := []api.Chat{
+		{ID: "a", Title: "Alpha", Network: "slack"},
+		{ID: "b", Title: "Beta", Network: "telegram"},
+		{ID: "c", Title: "Gamma", Network: "slack"},
+	}
+	sender := &recordingSender{fail: map[string]bool{"b": true}}
+	msg := TextMessage("hi")
+
+	report := Run(context.Background(), sender, chats, msg, Options{Concurrency: 2})
+
+	if report.Sent != 2 || report.Failed != 1 || len(report.Results) != 3 {
+		t.Fatalf("report = %+v", report)
+	}
+	// Results stay in target order.
+	for i, want := range []string{"a", "b", "c"} {
+		if report.Results[i].ChatID != want {
+			t.Errorf("Results[%d].ChatID = %q, want %q", i, report.Results[i].ChatID, want)
+		}
+	}
+	if report.Results[0].MessageID != "m-a-hi" || report.Results[1].Error != "rate limited" {
+		t.Errorf("results = %+v", report.Results)
+	}
+	if got := report.Summary(); got != "Sent to 2 of 3 chats, 1 failed" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+func TestRunPacesPerNetwork(t *testing.T) {
+	chats := []api.Chat{
+		{ID: "s1", Network: "slack"},
+		{ID: "s2", Network: "slack"},
+		{ID: "t1", Network: "telegram"},
+	}
+	sender := &recordingSender{}
+	msg := TextMessage("x")
+	interval := 100 * time.Millisecond
+
+	start := time.Now()
+	Run(context.Background(), sender, chats, msg, Options{Concurrency: 3, Interval: interval})
+
+	first, second := sender.sends["s1"][0], sender.sends["s2"][0]
+	if second.Before(first) {
+		first, second = second, first
+	}
+	if gap := second.Sub(first); gap < interval-10*time.Millisecond {
+		t.Errorf("slack sends %v apart, want at least %v", gap, interval)
+	}
+	if d := sender.sends["t1"][0].Sub(start); d > interval/2 {
+		t.Errorf("telegram send waited %v behind slack", d)
+	}
+}
+
+func TestRunSendsOnNetworksInParallel(t *testing.T) {
+	// Slack chats come first, but a single worker must not leave telegram
+	// waiting while slack sits out its interval.
+	chats := []api.Chat{
+		{ID: "s1", Network: "slack"},
+		{ID: "s2", Network: "slack"},
+		{ID: "s3", Network: "slack"},
+		{ID: "t1", Network: "telegram"},
+		{ID: "t2", Network: "telegram"},
+		{ID: "t3", Network: "telegram"},
+	}
+	sender := &recordingSender{}
+	msg := TextMessage("x")
+	interval := 100 * time.Millisecond
+
+	start := time.Now()
+	report := Run(context.Background(), sender, chats, msg, Options{Concurrency: 1, Interval: interval})
+	if report.Sent != 6 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	for n, pair := range [][2]string{{"s1", "t1"}, {"s2", "t2"}, {"s3", "t3"}} {
+		slack, telegram := sender.sends[pair[0]][0], sender.sends[pair[1]][0]
+		if d := telegram.Sub(slack); d < 0 || d > interval/2 {
+			t.Errorf("%s sent %v after %s, want both in slot %d", pair[1], d, pair[0], n)
+		}
+	}
+	if d := time.Since(start); d > 2*interval+interval/2 {
+		t.Errorf("broadcast took %v, want about %v", d, 2*interval)
+	}
+}
+
+func TestRunSkipsAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	msg := TextMessage("x")
+
+	report := Run(ctx, &recordingSender{}, []api.Chat{{ID: "a"}, {ID: "b"}}, msg, Options{})
+	if report.Skipped != 2 || report.Sent != 0 {
+		t.Errorf("report = %+v, want all skipped", report)
+	}
+}
+
+func TestTextMessageIsLiteral(t *testing.T) {
+	got, err := TextMessage("Use {{.Title}} in templates")(api.Chat{Title: "Ops"})
+	if err != nil || got != "Use {{.Title}} in templates" {
+		t.Errorf("TextMessage() = %q, %v, want the text unchanged", got, err)
+	}
+}
+
+func TestTextTemplateMessage(t *testing.T) {
+	msg, err := TextTemplateMessage("Hi {{.Title}} on {{.Network}}")
+	if err != nil {
+		t.Fatalf("TextTemplateMessage() error: %v", err)
+	}
+	got, err := msg(api.Chat{Title: "Ops", AccountID: "slackgo.acme"})
+	if err != nil || got != "Hi Ops on slackgo.acme" {
+		t.Errorf("render = %q, %v", got, err)
+	}
+
+	if _, err := TextTemplateMessage("Hi {{.Title"); err == nil {
+		t.Error("TextTemplateMessage() should reject a malformed template")
+	}
+	bad, _ := TextTemplateMessage("Hi {{.Vars.nope}}")
+	if _, err := bad(api.Chat{}); err == nil {
+		t.Error("rendering a missing var should fail")
+	}
+}
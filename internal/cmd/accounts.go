@@ -1,9 +1,11 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -21,21 +23,11 @@ func newAccountsCmd() *cobra.Command {
 				return err
 			}
 
-			resp, err := client.Get(cmd.Context(), "/v1/accounts")
+			accounts, err := client.Accounts().List(cmd.Context())
 			if err != nil {
-				return api.UserFriendlyError(err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if err := api.ParseErrorWithContext(resp, ""); err != nil {
 				return err
 			}
 
-			var accounts []api.Account
-			if err := json.NewDecoder(resp.Body).Decode(&accounts); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
-			}
-
 			return outfmt.Output(cmd.Context(), accounts, func(w io.Writer) {
 				tw := outfmt.NewTableWriter(w)
 				tw.SetHeader([]string{"ID", "Network", "User"})
@@ -52,31 +44,53 @@ func newAccountsCmd() *cobra.Command {
 	}
 }
 
-func getClient() (*api.Client, error) {
-	store, err := openSecretsStore()
-	if err != nil {
-		return nil, fmt.Errorf("failed to open keyring: %w", err)
+// accountIDs returns the --account filter as a list of IDs, falling back to
+// the active profile's default filter.
+func accountIDs() []string {
+	if flags.Account == "" && activeProfile != nil {
+		return splitList(activeProfile.Account)
 	}
+	return splitList(flags.Account)
+}
 
-	accounts, err := store.List()
+// networkAccountIDs resolves network names (or account IDs) to the IDs of
+// the connected accounts on them, case-insensitively.
+func networkAccountIDs(ctx context.Context, client *api.Client, networks []string) ([]string, error) {
+	accounts, err := client.Accounts().List(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list accounts: %w", err)
-	}
-
-	if len(accounts) == 0 {
-		return nil, fmt.Errorf("no tokens configured. Run: beeper auth add")
+		return nil, err
 	}
-
-	// Use first account (or could check flags.Account)
-	creds, err := store.Get(accounts[0].Name)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get credentials: %w", err)
+	var ids []string
+	for _, n := range networks {
+		found := false
+		for _, a := range accounts {
+			if strings.EqualFold(n, a.NetworkName) || strings.EqualFold(n, a.ID) {
+				found = true
+				if !slices.Contains(ids, a.ID) {
+					ids = append(ids, a.ID)
+				}
+			}
+		}
+		if !found {
+			var have []string
+			for _, a := range accounts {
+				if !slices.Contains(have, a.NetworkName) {
+					have = append(have, a.NetworkName)
+				}
+			}
+			return nil, fmt.Errorf("no connected account on network %q (have: %s)", n, strings.Join(have, ", "))
+		}
 	}
+	return ids, nil
+}
 
-	opts := []api.ClientOption{}
-	if flags.Debug {
-		opts = append(opts, api.WithDebug(true))
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
 	}
-
-	return api.NewClient(api.DefaultBaseURL, creds.Token, opts...), nil
+	return out
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/salmonumbrella/beeper-cli/internal/api"
 	"github.com/salmonumbrella/beeper-cli/internal/auth"
+	"github.com/salmonumbrella/beeper-cli/internal/config"
 	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
 	"github.com/salmonumbrella/beeper-cli/internal/secrets"
 )
@@ -28,24 +31,42 @@ func newAuthCmd() *cobra.Command {
 	cmd.AddCommand(newAuthRemoveCmd())
 	cmd.AddCommand(newAuthLoginCmd())
 	cmd.AddCommand(newAuthTestCmd())
+	cmd.AddCommand(newAuthUseCmd())
+	cmd.AddCommand(newAuthBackendsCmd())
+	cmd.AddCommand(newAuthStatusCmd())
 
 	return cmd
 }
 
 func newAuthAddCmd() *cobra.Command {
-	var tokenFlag string
+	var (
+		tokenFlag      string
+		defaultAccount string
+	)
 
 	cmd := &cobra.Command{
-		Use:   "add [name]",
+		Use:   "add [profile]",
 		Short: "Add a new token",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Add a token under a named profile (default: "default", or --profile).
+
+Use --token-stdin to pipe the token in without a prompt:
+  op read op://vault/beeper/token | beeper auth add --token-stdin
+
+Each profile keeps its own token, API endpoint (from --api-url or
+BEEPER_API_URL at the time it is added) and default --account filter:
+  beeper auth add work --default-account slack
+  beeper --api-url unix:///tmp/beeper.sock auth add devbox`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := "default"
-			if len(args) > 0 {
-				name = args[0]
-			}
+			name := profileArg(args)
 
 			token := tokenFlag
+			if token == "" && flags.TokenStdin {
+				var err error
+				if token, err = stdinToken(); err != nil {
+					return err
+				}
+			}
 			if token == "" {
 				fmt.Print("Enter token: ")
 				tokenBytes, err := term.ReadPassword(int(syscall.Stdin))
@@ -61,25 +82,24 @@ func newAuthAddCmd() *cobra.Command {
 			}
 
 			// Validate token
-			client := api.NewClient(api.DefaultBaseURL, token)
-			resp, err := client.Get(cmd.Context(), "/v1/accounts")
+			client, err := newClient(token, nil)
 			if err != nil {
-				return api.UserFriendlyError(err)
+				return err
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if err := api.ParseErrorWithContext(resp, ""); err != nil {
+			if _, err := client.Accounts().List(cmd.Context()); err != nil {
 				return err
 			}
 
 			// Save to keyring
-			store, err := secrets.NewStore()
+			store, err := openSecretsStore()
 			if err != nil {
 				return fmt.Errorf("failed to open keyring: %w", err)
 			}
 
 			if err := store.Set(name, secrets.Credentials{
 				Token:     token,
+				BaseURL:   explicitEndpoint(),
+				Account:   defaultAccount,
 				CreatedAt: time.Now(),
 			}); err != nil {
 				return fmt.Errorf("failed to save token: %w", err)
@@ -90,7 +110,8 @@ func newAuthAddCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&tokenFlag, "token", "", "Token (or enter interactively)")
+	cmd.Flags().StringVar(&tokenFlag, "token", "", "Token (or use --token-stdin, or enter interactively)")
+	cmd.Flags().StringVar(&defaultAccount, "default-account", "", "Default --account filter for this profile")
 
 	return cmd
 }
@@ -98,9 +119,9 @@ func newAuthAddCmd() *cobra.Command {
 func newAuthListCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
-		Short: "List configured tokens",
+		Short: "List configured profiles",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			store, err := secrets.NewStore()
+			store, err := openSecretsStore()
 			if err != nil {
 				return fmt.Errorf("failed to open keyring: %w", err)
 			}
@@ -115,11 +136,25 @@ func newAuthListCmd() *cobra.Command {
 				return nil
 			}
 
+			active, _ := resolveProfileName(accounts)
+
 			return outfmt.Output(cmd.Context(), accounts, func(w io.Writer) {
 				tw := outfmt.NewTableWriter(w)
-				tw.SetHeader([]string{"Name", "Created"})
+				tw.SetHeader([]string{"", "Name", "Endpoint", "Account", "Created"})
 				for _, a := range accounts {
-					tw.Append([]string{a.Name, a.CreatedAt.Format("2006-01-02 15:04")})
+					marker := ""
+					if a.Name == active {
+						marker = "*"
+					}
+					endpoint := a.BaseURL
+					if endpoint == "" {
+						endpoint = "-"
+					}
+					account := a.Account
+					if account == "" {
+						account = "-"
+					}
+					tw.Append([]string{marker, a.Name, endpoint, account, a.CreatedAt.Format("2006-01-02 15:04")})
 				}
 				tw.Render()
 			})
@@ -129,13 +164,13 @@ func newAuthListCmd() *cobra.Command {
 
 func newAuthRemoveCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "remove <name>",
+		Use:   "remove <profile>",
 		Short: "Remove a token",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
-			store, err := secrets.NewStore()
+			store, err := openSecretsStore()
 			if err != nil {
 				return fmt.Errorf("failed to open keyring: %w", err)
 			}
@@ -144,6 +179,18 @@ func newAuthRemoveCmd() *cobra.Command {
 				return fmt.Errorf("failed to remove token: %w", err)
 			}
 
+			// Forget the default if it pointed at the removed profile
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if cfg.DefaultProfile == name {
+				cfg.DefaultProfile = ""
+				if err := cfg.Save(); err != nil {
+					return err
+				}
+			}
+
 			fmt.Printf("Token '%s' removed\n", name)
 			return nil
 		},
@@ -152,52 +199,262 @@ func newAuthRemoveCmd() *cobra.Command {
 
 func newAuthTestCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "test [name]",
+		Use:   "test [profile]",
 		Short: "Test a token",
+		Long:  "Test a profile's token. Without an argument, tests the active profile.",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := "default"
+			store, err := openSecretsStore()
+			if err != nil {
+				return fmt.Errorf("failed to open keyring: %w", err)
+			}
+
+			var creds *secrets.Credentials
 			if len(args) > 0 {
-				name = args[0]
+				creds, err = loadProfile(store, args[0])
+			} else {
+				creds, err = activeCredentials(store)
 			}
+			if err != nil {
+				return err
+			}
+			name := creds.Name
 
-			store, err := secrets.NewStore()
+			client, err := newClient(creds.Token, creds)
 			if err != nil {
-				return fmt.Errorf("failed to open keyring: %w", err)
+				return err
+			}
+			if _, err := client.Accounts().List(cmd.Context()); err != nil {
+				return err
 			}
 
-			creds, err := store.Get(name)
+			fmt.Printf("Token '%s' is valid\n", name)
+			return nil
+		},
+	}
+}
+
+// authStatus is the output of `auth status`.
+type authStatus struct {
+	Source   string `json:"source"`
+	Profile  string `json:"profile,omitempty"`
+	Endpoint string `json:"endpoint"`
+	Valid    bool   `json:"valid"`
+	Error    string `json:"error,omitempty"`
+}
+
+func newAuthStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show which token is in use and whether it works",
+		Long: `Show where the current token comes from and test it against the API.
+
+Tokens are resolved in this order:
+  1. BEEPER_TOKEN environment variable
+  2. --token-stdin
+  3. token_command in the config file
+  4. the active keyring profile`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tok, err := resolveToken()
 			if err != nil {
-				return fmt.Errorf("token '%s' not found. Run: beeper auth add", name)
+				return err
 			}
 
-			client := api.NewClient(api.DefaultBaseURL, creds.Token)
-			resp, err := client.Get(cmd.Context(), "/v1/accounts")
+			address, _, err := resolveEndpoint(tok.Profile)
 			if err != nil {
-				return api.UserFriendlyError(err)
+				return err
+			}
+			if address == "" {
+				address = api.DefaultBaseURL
 			}
-			defer func() { _ = resp.Body.Close() }()
 
-			if err := api.ParseErrorWithContext(resp, ""); err != nil {
+			status := authStatus{Source: tok.Source, Endpoint: address}
+			if tok.Profile != nil {
+				status.Profile = tok.Profile.Name
+			}
+
+			client, err := newClient(tok.Token, tok.Profile)
+			if err != nil {
 				return err
 			}
+			if _, err := client.Accounts().List(cmd.Context()); err != nil {
+				status.Error = err.Error()
+			} else {
+				status.Valid = true
+			}
 
-			fmt.Printf("Token '%s' is valid\n", name)
+			return outfmt.Output(cmd.Context(), status, func(w io.Writer) {
+				source := status.Source
+				if status.Profile != "" {
+					source += fmt.Sprintf(" (profile '%s')", status.Profile)
+				}
+				_, _ = fmt.Fprintf(w, "Source:   %s\n", source)
+				_, _ = fmt.Fprintf(w, "Endpoint: %s\n", status.Endpoint)
+				if status.Valid {
+					_, _ = fmt.Fprintf(w, "Status:   valid\n")
+				} else {
+					_, _ = fmt.Fprintf(w, "Status:   invalid (%s)\n", status.Error)
+				}
+			})
+		},
+	}
+}
+
+func newAuthUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <profile>",
+		Short: "Set the default profile",
+		Long: `Set the profile used when neither --profile nor BEEPER_PROFILE is given.
+
+The choice is recorded as default_profile in the config file.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			store, err := openSecretsStore()
+			if err != nil {
+				return fmt.Errorf("failed to open keyring: %w", err)
+			}
+			if _, err := loadProfile(store, name); err != nil {
+				return err
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			cfg.DefaultProfile = name
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			fmt.Printf("Default profile set to '%s'\n", name)
 			return nil
 		},
 	}
 }
 
+func newAuthBackendsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "backends",
+		Short: "List credential backends available on this machine",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selected, err := keyringBackend()
+			if err != nil {
+				return err
+			}
+			fileDir, err := keyringFileDir()
+			if err != nil {
+				return err
+			}
+
+			statuses := secrets.Backends(fileDir)
+			return outfmt.Output(cmd.Context(), statuses, func(w io.Writer) {
+				tw := outfmt.NewTableWriter(w)
+				tw.SetHeader([]string{"", "Backend", "Status", "Notes"})
+				for _, st := range statuses {
+					marker := ""
+					if st.Name == selected || (selected == "" && st.Default && st.Available) {
+						marker = "*"
+					}
+					status := "available"
+					if !st.Available {
+						status = "unavailable"
+					}
+					notes := st.Reason
+					if st.Name == secrets.BackendFile && st.Available {
+						notes = "encrypted file in " + fileDir + "; password from BEEPER_KEYRING_PASSWORD or prompt"
+					}
+					tw.Append([]string{marker, st.Name, status, notes})
+				}
+				tw.Render()
+			})
+		},
+	}
+}
+
+// openSecretsStore opens the credential store using the backend chosen by
+// --keyring-backend, BEEPER_KEYRING_BACKEND or keyring_backend in config.
 func openSecretsStore() (secrets.Store, error) {
-	return secrets.NewStore()
+	backend, err := keyringBackend()
+	if err != nil {
+		return nil, err
+	}
+	fileDir, err := keyringFileDir()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := secrets.NewStore(
+		secrets.WithBackend(backend),
+		secrets.WithFileDir(fileDir),
+		secrets.WithPasswordFunc(keyringPassword),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w (see: beeper auth backends)", err)
+	}
+	return store, nil
+}
+
+func keyringBackend() (string, error) {
+	if flags.KeyringBackend != "" {
+		return flags.KeyringBackend, nil
+	}
+	if v := os.Getenv("BEEPER_KEYRING_BACKEND"); v != "" {
+		return v, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	return cfg.KeyringBackend, nil
+}
+
+func keyringFileDir() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "keyring"), nil
+}
+
+// keyringPassword unlocks the encrypted file backend from
+// BEEPER_KEYRING_PASSWORD, or prompts when attached to a terminal.
+func keyringPassword(prompt string) (string, error) {
+	if v, ok := os.LookupEnv("BEEPER_KEYRING_PASSWORD"); ok {
+		return v, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("file keyring is locked: set BEEPER_KEYRING_PASSWORD")
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "%s: ", prompt)
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read keyring password: %w", err)
+	}
+	return string(password), nil
 }
 
 func newAuthLoginCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "login",
+		Use:   "login [profile]",
 		Short: "Login via browser (interactive token setup)",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			server, err := auth.NewSetupServer()
+			store, err := openSecretsStore()
+			if err != nil {
+				return fmt.Errorf("failed to open keyring: %w", err)
+			}
+
+			server, err := auth.NewSetupServer(
+				auth.WithClientFactory(func(token string) (*api.Client, error) {
+					return newClient(token, nil)
+				}),
+				auth.WithBaseURL(explicitEndpoint()),
+				auth.WithProfile(profileArg(args)),
+				auth.WithStore(store),
+			)
 			if err != nil {
 				return fmt.Errorf("failed to create setup server: %w", err)
 			}
@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/broadcast"
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+	"github.com/salmonumbrella/beeper-cli/internal/templates"
+)
+
+func newMessagesBroadcastCmd() *cobra.Command {
+	var (
+		to          []string
+		toFile      string
+		filter      string
+		networks    string
+		chatType    string
+		inboxes     string
+		text        string
+		textFile    string
+		dryRun      bool
+		concurrency int
+		interval    time.Duration
+		reportPath  string
+		tmplName    string
+		vars        []string
+		textTmpl    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "broadcast",
+		Short: "Send one message to many chats",
+		Long: `Send the same message to many chats, paced per network.
+
+Pick chats by name or ID, from a file, or by filtering your chat list:
+  beeper messages broadcast --to "Team A" --to "Team B" --text "Deploy at 5pm"
+  beeper messages broadcast --to-file chats.txt --text-file announcement.md
+  beeper messages broadcast --filter '(?i)^eng-' --type group --text "Freeze starts now"
+
+chats.txt has one chat ID or name per line; blank lines and lines starting
+with '#' are ignored. Names must identify exactly one chat: an ambiguous
+name stops the broadcast before anything is sent, listing the candidates.
+
+--filter is a regular expression matched against chat titles across the
+primary and low-priority inboxes (see --inbox); --network and --type narrow
+every selection.
+
+The text is sent as written. With --text-template it is rendered per chat
+instead, so it may use fields such as {{.Title}} or {{.FirstNames}}, e.g.
+--text "Hi {{.FirstNames}}!" --text-template. Use --template with --var to
+send a saved template (see 'beeper templates').
+
+Sends on the same network are spaced by --interval, with at most
+--concurrency in flight. Use --dry-run to preview the chats and text. The
+command ends with a per-chat table of message IDs and failures; with
+-o json, or --report FILE, the report is written as JSON.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if text == "-" && toFile == "-" {
+				return fmt.Errorf("--text - and --to-file - both read stdin")
+			}
+			var err error
+			if text, err = readMessageText(text, textFile); err != nil {
+				return err
+			}
+			var msg broadcast.Message
+			if tmplName != "" {
+				values, err := templates.ParseVars(vars)
+				if err != nil {
+					return err
+				}
+				dir, err := templates.Dir()
+				if err != nil {
+					return err
+				}
+				tmpl, err := templates.Load(dir, tmplName)
+				if err != nil {
+					return err
+				}
+				msg = broadcast.TemplateMessage(tmpl, values)
+			} else {
+				if text == "" {
+					return fmt.Errorf("--text, --text-file or --template is required")
+				}
+				if len(vars) > 0 {
+					return fmt.Errorf("--var needs --template")
+				}
+				msg = broadcast.TextMessage(text)
+				if textTmpl {
+					m, err := broadcast.TextTemplateMessage(text)
+					if err != nil {
+						return err
+					}
+					msg = m
+				}
+			}
+
+			refs := slices.Clone(to)
+			if toFile != "" {
+				fileRefs, err := readChatList(toFile)
+				if err != nil {
+					return err
+				}
+				refs = append(refs, fileRefs...)
+			}
+			if len(refs) == 0 && filter == "" {
+				return fmt.Errorf("choose chats with --to, --to-file or --filter")
+			}
+			var titleRe *regexp.Regexp
+			if filter != "" {
+				re, err := regexp.Compile(filter)
+				if err != nil {
+					return fmt.Errorf("invalid --filter pattern: %w", err)
+				}
+				titleRe = re
+			}
+			if chatType != "" && chatType != "dm" && chatType != "group" {
+				return fmt.Errorf("--type must be dm or group")
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+
+			chats, err := broadcastTargets(cmd, client, refs, titleRe, splitList(inboxes))
+			if err != nil {
+				return err
+			}
+			chats = slices.DeleteFunc(chats, func(c api.Chat) bool {
+				return !chatMatchesNetworks(c, splitList(networks)) || (chatType != "" && c.Type != chatType)
+			})
+			if len(chats) == 0 {
+				return fmt.Errorf("no chats selected")
+			}
+
+			if dryRun {
+				return printBroadcastPreview(cmd, chats, msg)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			stderr := cmd.ErrOrStderr()
+			_, _ = fmt.Fprintf(stderr, "Sending to %d chats...\n", len(chats))
+			report := broadcast.Run(ctx, client.Messages(), chats, msg, broadcast.Options{
+				Concurrency: concurrency,
+				Interval:    interval,
+				Progress: func(r broadcast.Result) {
+					mark := "✓"
+					if r.Status != broadcast.StatusSent {
+						mark = "✗"
+					}
+					_, _ = fmt.Fprintf(stderr, "  %s %s\n", mark, r.Title)
+				},
+			})
+
+			if reportPath != "" {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(reportPath, append(data, '\n'), 0o600); err != nil {
+					return fmt.Errorf("failed to write report: %w", err)
+				}
+			}
+
+			colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(cmd.Context()))
+			if err := outfmt.Output(cmd.Context(), report, func(w io.Writer) {
+				_, _ = fmt.Fprintln(w)
+				tw := outfmt.NewTableWriter(w)
+				headers := []string{"Chat", "Network", "Status", "Message ID / Error"}
+				if colorEnabled {
+					for i, h := range headers {
+						headers[i] = outfmt.Colorize(h, outfmt.Bold, true)
+					}
+				}
+				tw.SetHeader(headers)
+				for _, r := range report.Results {
+					detail := r.MessageID
+					if r.Error != "" {
+						detail = truncate(r.Error, 60)
+					}
+					tw.Append([]string{truncate(r.Title, 30), r.Network, r.Status, detail})
+				}
+				tw.Render()
+				_, _ = fmt.Fprintf(w, "\n%s\n", report.Summary())
+			}); err != nil {
+				return err
+			}
+
+			if report.Failed > 0 || report.Skipped > 0 {
+				return fmt.Errorf("broadcast incomplete: %s", report.Summary())
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&to, "to", nil, "Chat ID or exact name (repeatable)")
+	cmd.Flags().StringVar(&toFile, "to-file", "", "File of chat IDs or names, one per line ('-' reads stdin)")
+	cmd.Flags().StringVar(&filter, "filter", "", "Regular expression matched against chat titles")
+	cmd.Flags().StringVar(&networks, "network", "", "Only chats on these networks or account IDs, comma-separated")
+	cmd.Flags().StringVar(&chatType, "type", "", "Only chats of this type: dm or group")
+	cmd.Flags().StringVar(&inboxes, "inbox", "primary,low-priority", "Inboxes --filter searches, comma-separated")
+	cmd.Flags().StringVar(&text, "text", "", "Message text ('-' reads stdin)")
+	cmd.Flags().StringVar(&textFile, "text-file", "", "Read the message text from a file")
+	cmd.Flags().StringVar(&tmplName, "template", "", "Render the message from a saved template")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable key=value (repeatable)")
+	cmd.Flags().BoolVar(&textTmpl, "text-template", false, "Render --text or --text-file as a template per chat")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the chats and text without sending")
+	cmd.Flags().IntVar(&concurrency, "concurrency", broadcast.DefaultConcurrency, "Maximum sends in flight")
+	cmd.Flags().DurationVar(&interval, "interval", broadcast.DefaultInterval, "Minimum gap between sends on the same network")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Also write the JSON report to this file")
+	cmd.MarkFlagsMutuallyExclusive("text", "text-file", "template")
+	cmd.MarkFlagsMutuallyExclusive("text-template", "template")
+
+	return cmd
+}
+
+// readChatList reads chat references from a file, one per line, skipping
+// blank lines and # comments.
+func readChatList(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open chat list: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	var refs []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		refs = append(refs, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read chat list: %w", err)
+	}
+	return refs, nil
+}
+
+// broadcastTargets resolves refs strictly and adds the chats whose titles
+// match titleRe, without duplicates. Every unresolvable ref is reported
+// together, before anything is sent.
+func broadcastTargets(cmd *cobra.Command, client *api.Client, refs []string, titleRe *regexp.Regexp, inboxes []string) ([]api.Chat, error) {
+	var (
+		chats    []api.Chat
+		problems []string
+	)
+	add := func(c api.Chat) {
+		if !slices.ContainsFunc(chats, func(x api.Chat) bool { return x.ID == c.ID }) {
+			chats = append(chats, c)
+		}
+	}
+
+	for _, ref := range refs {
+		var (
+			chat *api.Chat
+			err  error
+		)
+		if looksLikeChatID(ref) {
+			chat, err = client.Chats().Get(cmd.Context(), ref)
+		} else {
+			chat, err = findChatByName(cmd, client, ref, true)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", ref, err))
+			continue
+		}
+		add(*chat)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("could not resolve %d chats; nothing was sent\n%s", len(problems), strings.Join(problems, "\n"))
+	}
+
+	if titleRe != nil {
+		scan, err := client.Chats().Enumerate(cmd.Context(), api.EnumerateParams{Inboxes: inboxes, AccountIDs: accountIDs()})
+		if err != nil {
+			return nil, err
+		}
+		if !scan.Complete() {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", scan.Summary())
+		}
+		for _, c := range scan.Chats {
+			if titleRe.MatchString(c.Title) {
+				add(c)
+			}
+		}
+	}
+	return chats, nil
+}
+
+// chatMatchesNetworks reports whether a chat is on one of networks (names or
+// account IDs, case-insensitive). An empty list matches every chat.
+func chatMatchesNetworks(c api.Chat, networks []string) bool {
+	if len(networks) == 0 {
+		return true
+	}
+	for _, n := range networks {
+		if strings.EqualFold(n, c.Network) || strings.EqualFold(n, c.AccountID) {
+			return true
+		}
+	}
+	return false
+}
+
+// printBroadcastPreview shows what a broadcast would send.
+func printBroadcastPreview(cmd *cobra.Command, chats []api.Chat, msg broadcast.Message) error {
+	preview := make([]broadcast.Result, 0, len(chats))
+	for _, c := range chats {
+		r := broadcast.Result{ChatID: c.ID, Title: c.Title, Network: broadcast.NetworkKey(c), Status: "dry-run"}
+		text, err := msg(c)
+		if err != nil {
+			r.Error = err.Error()
+		}
+		r.Text = text
+		preview = append(preview, r)
+	}
+
+	return outfmt.Output(cmd.Context(), preview, func(w io.Writer) {
+		_, _ = fmt.Fprintf(w, "Would send to %d chats:\n", len(preview))
+		for _, r := range preview {
+			_, _ = fmt.Fprintf(w, "  - %s (%s) %s\n", r.Title, r.Network, r.ChatID)
+			if r.Error != "" {
+				_, _ = fmt.Fprintf(w, "      error: %s\n", r.Error)
+				continue
+			}
+			for line := range strings.SplitSeq(r.Text, "\n") {
+				_, _ = fmt.Fprintf(w, "      > %s\n", line)
+			}
+		}
+	})
+}
@@ -1,11 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
-	"net/url"
-	"strings"
+	"iter"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -34,6 +33,8 @@ func newChatsListCmd() *cobra.Command {
 		unreadOnly bool
 		inbox      string
 		limit      int
+		all        bool
+		max        int
 	)
 
 	cmd := &cobra.Command{
@@ -41,10 +42,12 @@ func newChatsListCmd() *cobra.Command {
 		Short: "List chats",
 		Long: `List recent chats from your inbox.
 
-Note: This command returns only the ~25 most recent chats due to API limitations.
-To find ALL chats, use 'beeper chats search <query>' which searches your entire history.
+By default only the first page (~25 most recent chats) is returned. Use --all
+to follow pagination through every page, streaming results as they arrive:
+  beeper chats list --all
+  beeper chats list --all --max 500 -o json
 
-To archive all read chats (searching through ALL chats), use:
+To archive all read chats across every inbox, use:
   beeper chats archive-read`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := getClient()
@@ -52,36 +55,20 @@ To archive all read chats (searching through ALL chats), use:
 				return err
 			}
 
-			params := url.Values{}
-			if inbox != "" {
-				params.Set("inbox", inbox)
-			}
-			if flags.Account != "" {
-				for _, id := range strings.Split(flags.Account, ",") {
-					params.Add("accountIDs", strings.TrimSpace(id))
-				}
+			params := api.ListChatsParams{
+				Inbox:      inbox,
+				AccountIDs: accountIDs(),
 			}
 
-			path := "/v1/chats"
-			if len(params) > 0 {
-				path += "?" + params.Encode()
+			if all {
+				return streamChats(cmd, client.Chats().All(cmd.Context(), params, max), unreadOnly)
 			}
 
-			resp, err := client.Get(cmd.Context(), path)
+			result, err := client.Chats().List(cmd.Context(), params)
 			if err != nil {
-				return api.UserFriendlyError(err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if err := api.ParseErrorWithContext(resp, ""); err != nil {
 				return err
 			}
 
-			var result api.ListChatsResponse
-			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
-			}
-
 			chats := result.Items
 			if unreadOnly {
 				filtered := make([]api.Chat, 0)
@@ -128,10 +115,54 @@ To archive all read chats (searching through ALL chats), use:
 	cmd.Flags().BoolVar(&unreadOnly, "unread", false, "Show only unread chats")
 	cmd.Flags().StringVar(&inbox, "inbox", "", "Filter by inbox: primary, low-priority, archive")
 	cmd.Flags().IntVar(&limit, "limit", 0, "Limit number of results")
+	cmd.Flags().BoolVar(&all, "all", false, "Follow pagination and stream every chat")
+	cmd.Flags().IntVar(&max, "max", 0, "With --all, stop after this many chats (0 = no limit)")
 
 	return cmd
 }
 
+// streamChats prints chats as pages arrive: one JSON object per line in JSON
+// mode, or fixed-width table rows in text mode.
+func streamChats(cmd *cobra.Command, chats iter.Seq2[api.Chat, error], unreadOnly bool) error {
+	ctx := cmd.Context()
+	colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(ctx))
+
+	var st *outfmt.StreamTable
+	if outfmt.GetFormat(ctx) != "json" {
+		st = outfmt.NewStreamTable(os.Stdout, 20, 30, 10, 6)
+		headers := []string{"ID", "Name", "Network", "Unread", "Last Activity"}
+		if colorEnabled {
+			for i, h := range headers {
+				headers[i] = outfmt.Colorize(h, outfmt.Bold, true)
+			}
+		}
+		st.Row(headers...)
+	}
+
+	count := 0
+	for c, err := range chats {
+		if err != nil {
+			return err
+		}
+		if unreadOnly && c.UnreadCount == 0 {
+			continue
+		}
+		count++
+		if err := outfmt.Output(ctx, c, func(io.Writer) {
+			unread := ""
+			if c.UnreadCount > 0 {
+				unread = fmt.Sprintf("%d", c.UnreadCount)
+			}
+			st.Row(truncate(c.ID, 20), truncate(c.Title, 30), c.Network, unread, formatTime(c.LastActivity))
+		}); err != nil {
+			return err
+		}
+	}
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%d chats\n", count)
+	return nil
+}
+
 func newChatsGetCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get <chat-id>",
@@ -145,21 +176,11 @@ func newChatsGetCmd() *cobra.Command {
 				return err
 			}
 
-			resp, err := client.Get(cmd.Context(), "/v1/chats/"+url.PathEscape(chatID))
+			chat, err := client.Chats().Get(cmd.Context(), chatID)
 			if err != nil {
-				return api.UserFriendlyError(err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if err := api.ParseErrorWithContext(resp, "Chat"); err != nil {
 				return err
 			}
 
-			var chat api.Chat
-			if err := json.NewDecoder(resp.Body).Decode(&chat); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
-			}
-
 			return outfmt.Output(cmd.Context(), chat, func(w io.Writer) {
 				_, _ = fmt.Fprintf(w, "ID:          %s\n", chat.ID)
 				_, _ = fmt.Fprintf(w, "Name:        %s\n", chat.Title)
@@ -197,29 +218,14 @@ func newChatsSearchCmd() *cobra.Command {
 				return err
 			}
 
-			params := url.Values{}
-			params.Set("query", query)
-			if flags.Account != "" {
-				for _, id := range strings.Split(flags.Account, ",") {
-					params.Add("accountIDs", strings.TrimSpace(id))
-				}
-			}
-
-			resp, err := client.Get(cmd.Context(), "/v1/chats/search?"+params.Encode())
+			result, err := client.Chats().Search(cmd.Context(), api.SearchChatsParams{
+				Query:      query,
+				AccountIDs: accountIDs(),
+			})
 			if err != nil {
-				return api.UserFriendlyError(err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if err := api.ParseErrorWithContext(resp, ""); err != nil {
 				return err
 			}
 
-			var result api.ListChatsResponse
-			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
-			}
-
 			colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(cmd.Context()))
 			return outfmt.Output(cmd.Context(), result.Items, func(w io.Writer) {
 				tw := outfmt.NewTableWriter(w)
@@ -279,14 +285,7 @@ You can specify the chat either by ID or by using --chat with a name:
 				return fmt.Errorf("either <chat-id> argument or --chat flag is required")
 			}
 
-			body := map[string]bool{"archived": !unarchive}
-			resp, err := client.Post(cmd.Context(), "/v1/chats/"+url.PathEscape(chatID)+"/archive", body)
-			if err != nil {
-				return api.UserFriendlyError(err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if err := api.ParseErrorWithContext(resp, "Chat"); err != nil {
+			if err := client.Chats().Archive(cmd.Context(), chatID, !unarchive); err != nil {
 				return err
 			}
 
@@ -313,44 +312,30 @@ func newChatsArchiveReadCmd() *cobra.Command {
 		Short: "Archive all read chats",
 		Long: `Archive all chats that have zero unread messages.
 
-This command searches through ALL your chats (not just the recent 25) and
-archives any chat where unreadCount is 0.
+This command pages through every chat in your primary and low-priority inboxes
+(not just the recent 25) and archives any chat where unreadCount is 0. It
+reports whether each inbox was scanned to the end.
 
-Use --dry-run to preview which chats would be archived without making changes.
-
-Note: The regular 'chats list' only returns ~25 recent chats. This command
-uses search to find ALL chats across your entire history.`,
+Use --dry-run to preview which chats would be archived without making changes.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := getClient()
 			if err != nil {
 				return err
 			}
 
-			// Search patterns to find all chats (vowels + common consonants cover most names)
-			patterns := []string{"a", "e", "i", "o", "u", "s", "t", "n", "r", "l", "m", "c", "d", "p", "b", "k", "g", "h", "j", "w", "y", "1", "2", "3"}
-
-			// Collect unique chat IDs to archive
-			toArchive := make(map[string]string) // id -> title
-
-			for _, pattern := range patterns {
-				params := url.Values{}
-				params.Set("query", pattern)
-
-				resp, err := client.Get(cmd.Context(), "/v1/chats/search?"+params.Encode())
-				if err != nil {
-					continue // Skip failed searches
-				}
-
-				var result api.ListChatsResponse
-				if resp.StatusCode == 200 {
-					_ = json.NewDecoder(resp.Body).Decode(&result)
-				}
-				_ = resp.Body.Close()
+			scan, err := client.Chats().Enumerate(cmd.Context(), api.EnumerateParams{
+				Inboxes:    []string{"primary", "low-priority"},
+				AccountIDs: accountIDs(),
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Println(scan.Summary())
 
-				for _, chat := range result.Items {
-					if chat.UnreadCount == 0 && !chat.IsArchived {
-						toArchive[chat.ID] = chat.Title
-					}
+			var toArchive []api.Chat
+			for _, chat := range scan.Chats {
+				if chat.UnreadCount == 0 && !chat.IsArchived {
+					toArchive = append(toArchive, chat)
 				}
 			}
 
@@ -361,28 +346,26 @@ uses search to find ALL chats across your entire history.`,
 
 			if dryRun {
 				fmt.Printf("Would archive %d chats:\n", len(toArchive))
-				for _, title := range toArchive {
-					fmt.Printf("  - %s\n", title)
+				for _, chat := range toArchive {
+					fmt.Printf("  - %s\n", chat.Title)
 				}
 				return nil
 			}
 
 			fmt.Printf("Archiving %d read chats...\n", len(toArchive))
 			archived := 0
-			for id, title := range toArchive {
-				body := map[string]bool{"archived": true}
-				resp, err := client.Post(cmd.Context(), "/v1/chats/"+url.PathEscape(id)+"/archive", body)
-				if err != nil {
-					fmt.Printf("  ✗ %s (error)\n", title)
+			for _, chat := range toArchive {
+				if err := client.Chats().Archive(cmd.Context(), chat.ID, true); err != nil {
+					fmt.Printf("  ✗ %s (%v)\n", chat.Title, err)
 					continue
 				}
-				_ = resp.Body.Close()
-				if resp.StatusCode == 200 {
-					fmt.Printf("  ✓ %s\n", title)
-					archived++
-				}
+				fmt.Printf("  ✓ %s\n", chat.Title)
+				archived++
 			}
 			fmt.Printf("\nArchived %d/%d chats\n", archived, len(toArchive))
+			if !scan.Complete() {
+				fmt.Println("Some inboxes could not be fully scanned; run again to pick up remaining chats")
+			}
 			return nil
 		},
 	}
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/config"
+	"github.com/salmonumbrella/beeper-cli/internal/secrets"
+)
+
+// activeProfile holds the credentials getClient resolved, so later lookups
+// such as accountIDs can apply the profile's defaults.
+var activeProfile *secrets.Credentials
+
+func getClient() (*api.Client, error) {
+	tok, err := resolveToken()
+	if err != nil {
+		return nil, err
+	}
+	activeProfile = tok.Profile
+
+	return newClient(tok.Token, tok.Profile)
+}
+
+// activeCredentials loads the credentials of the profile chosen by
+// resolveProfileName.
+func activeCredentials(store secrets.Store) (*secrets.Credentials, error) {
+	accounts, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no tokens configured. Run: beeper auth add")
+	}
+
+	name, err := resolveProfileName(accounts)
+	if err != nil {
+		return nil, err
+	}
+	return loadProfile(store, name)
+}
+
+// resolveProfileName picks the active profile, in order of precedence: the
+// --profile flag, BEEPER_PROFILE, default_profile in the config file, a
+// profile named "default", or the only stored profile.
+func resolveProfileName(accounts []secrets.AccountInfo) (string, error) {
+	if flags.Profile != "" {
+		return flags.Profile, nil
+	}
+	if v := os.Getenv("BEEPER_PROFILE"); v != "" {
+		return v, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	if cfg.DefaultProfile != "" {
+		return cfg.DefaultProfile, nil
+	}
+
+	names := make([]string, 0, len(accounts))
+	for _, a := range accounts {
+		if a.Name == "default" {
+			return a.Name, nil
+		}
+		names = append(names, a.Name)
+	}
+	if len(names) == 1 {
+		return names[0], nil
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("multiple profiles configured (%s). Pick one with --profile or run: beeper auth use <profile>", strings.Join(names, ", "))
+}
+
+// loadProfile returns the stored credentials for a profile.
+func loadProfile(store secrets.Store, name string) (*secrets.Credentials, error) {
+	creds, err := store.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("profile '%s' not found. Run: beeper auth add %s", name, name)
+	}
+	return creds, nil
+}
+
+// profileArg returns the profile named by a command's optional argument,
+// falling back to --profile and then "default".
+func profileArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	if flags.Profile != "" {
+		return flags.Profile
+	}
+	return "default"
+}
+
+// newClient builds an API client for token, reaching the endpoint chosen by
+// resolveEndpoint. creds may be nil when the token isn't stored yet.
+func newClient(token string, creds *secrets.Credentials) (*api.Client, error) {
+	address, caCert, err := resolveEndpoint(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, opts, err := api.ParseEndpoint(address, caCert)
+	if err != nil {
+		return nil, err
+	}
+	if flags.Debug {
+		opts = append(opts, api.WithDebug(true))
+	}
+
+	return api.NewClient(baseURL, token, opts...), nil
+}
+
+// resolveEndpoint picks the API address, in order of precedence: the
+// --api-url flag, BEEPER_API_URL, the endpoint stored with the credentials,
+// then the config file. An empty address means api.DefaultBaseURL. The CA
+// bundle comes from BEEPER_CA_CERT or the config file.
+func resolveEndpoint(creds *secrets.Credentials) (address, caCert string, err error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case flags.APIURL != "":
+		address = flags.APIURL
+	case os.Getenv("BEEPER_API_URL") != "":
+		address = os.Getenv("BEEPER_API_URL")
+	case creds != nil && creds.BaseURL != "":
+		address = creds.BaseURL
+	default:
+		address = cfg.APIURL
+	}
+
+	caCert = cfg.CACert
+	if v := os.Getenv("BEEPER_CA_CERT"); v != "" {
+		caCert = v
+	}
+	return address, caCert, nil
+}
+
+// explicitEndpoint returns the endpoint given on the command line or in the
+// environment, which `auth add` and `auth login` store with a new token.
+func explicitEndpoint() string {
+	if flags.APIURL != "" {
+		return flags.APIURL
+	}
+	return os.Getenv("BEEPER_API_URL")
+}
+
+// Token sources, in the order resolveToken tries them.
+const (
+	tokenSourceEnv     = "BEEPER_TOKEN"
+	tokenSourceStdin   = "stdin"
+	tokenSourceCommand = "token_command"
+	tokenSourceKeyring = "keyring"
+)
+
+// tokenCommandTimeout bounds how long a token_command may run.
+const tokenCommandTimeout = 30 * time.Second
+
+// resolvedToken is a token together with where it came from.
+type resolvedToken struct {
+	Token  string
+	Source string
+	// Profile is the keyring profile whose endpoint and default account
+	// apply: the token's own, or the one --profile names when the token
+	// comes from another source.
+	Profile *secrets.Credentials
+}
+
+// resolveToken finds a token, trying in order: BEEPER_TOKEN, --token-stdin,
+// token_command from the config file, and finally the active keyring profile.
+// The keyring is only opened when no other source supplies a token, or when
+// --profile asks for a profile's settings, so the CLI works in containers
+// with no keyring at all.
+func resolveToken() (*resolvedToken, error) {
+	tok, err := findToken()
+	if err != nil || tok.Profile != nil || flags.Profile == "" {
+		return tok, err
+	}
+
+	// An explicit --profile keeps its endpoint and default account even when
+	// the token comes from elsewhere.
+	store, err := openSecretsStore()
+	if err != nil {
+		return nil, fmt.Errorf("--profile %s: %w", flags.Profile, err)
+	}
+	creds, err := loadProfile(store, flags.Profile)
+	if err != nil {
+		return nil, err
+	}
+	tok.Profile = creds
+	return tok, nil
+}
+
+// findToken returns the first token source that supplies a token.
+func findToken() (*resolvedToken, error) {
+	if v := strings.TrimSpace(os.Getenv("BEEPER_TOKEN")); v != "" {
+		return &resolvedToken{Token: v, Source: tokenSourceEnv}, nil
+	}
+
+	if flags.TokenStdin {
+		token, err := stdinToken()
+		if err != nil {
+			return nil, err
+		}
+		return &resolvedToken{Token: token, Source: tokenSourceStdin}, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.TokenCommand != "" {
+		token, err := runTokenCommand(cfg.TokenCommand)
+		if err != nil {
+			return nil, err
+		}
+		return &resolvedToken{Token: token, Source: tokenSourceCommand}, nil
+	}
+
+	store, err := openSecretsStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keyring: %w", err)
+	}
+	creds, err := activeCredentials(store)
+	if err != nil {
+		return nil, err
+	}
+	return &resolvedToken{Token: creds.Token, Source: tokenSourceKeyring, Profile: creds}, nil
+}
+
+var (
+	stdinTokenOnce  sync.Once
+	stdinTokenValue string
+	stdinTokenErr   error
+)
+
+// stdinToken reads the token from standard input once per process.
+func stdinToken() (string, error) {
+	stdinTokenOnce.Do(func() {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			stdinTokenErr = fmt.Errorf("failed to read token from stdin: %w", err)
+			return
+		}
+		stdinTokenValue = strings.TrimSpace(string(data))
+		if stdinTokenValue == "" {
+			stdinTokenErr = fmt.Errorf("no token on stdin")
+		}
+	})
+	return stdinTokenValue, stdinTokenErr
+}
+
+// runTokenCommand runs a shell command, such as a password-manager CLI, and
+// returns its trimmed stdout as the token.
+func runTokenCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+	defer cancel()
+
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	c.Stderr = os.Stderr
+
+	out, err := c.Output()
+	if err != nil {
+		return "", fmt.Errorf("token_command failed: %w", err)
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("token_command printed no token")
+	}
+	return token, nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/export"
+)
+
+func newExportCmd() *cobra.Command {
+	var (
+		format string
+		out    string
+		since  string
+		until  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export <chat>",
+		Short: "Export a chat's history",
+		Long: `Export a chat's full message history to a file.
+
+The chat can be given by ID or by name. Formats:
+  jsonl     one api.Message JSON object per line (default)
+  markdown  readable transcript grouped by day
+  html      self-contained page, viewable offline
+  csv       one row per message, for spreadsheets
+  mbox      one email per message, for mail clients and archiving tools
+
+The format is taken from --format, or guessed from the --out extension.
+Replies include the quoted original, and senders are shown by name.
+
+Examples:
+  beeper export "Acme Support" --out acme.html
+  beeper export '!abc:beeper.com' --format md --since 2024-01-01 --until 2024-07-01
+  beeper export "Acme Support" --format csv > acme.csv`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = export.FormatForPath(out)
+			}
+			if format == "" {
+				format = export.FormatJSONL
+			}
+			format, err := export.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+
+			var r export.Range
+			if r.Since, err = parseDateFlag("since", since); err != nil {
+				return err
+			}
+			if r.Until, err = parseDateFlag("until", until); err != nil {
+				return err
+			}
+			if !r.Since.IsZero() && !r.Until.IsZero() && !r.Until.After(r.Since) {
+				return fmt.Errorf("--until must be after --since")
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+
+			chatID, err := resolveChatRef(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			transcript, err := export.Collect(cmd.Context(), client, chatID, r)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if out != "" && out != "-" {
+				f, err := os.Create(out)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", out, err)
+				}
+				defer func() { _ = f.Close() }()
+				w = f
+			}
+
+			if err := export.Write(w, format, transcript); err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+			if f, ok := w.(*os.File); ok && f != os.Stdout {
+				if err := f.Close(); err != nil {
+					return fmt.Errorf("failed to write %s: %w", out, err)
+				}
+			}
+
+			dest := "stdout"
+			if out != "" && out != "-" {
+				dest = out
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d messages from %s to %s (%s)\n",
+				len(transcript.Messages), transcript.Title(), dest, format)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Export format: "+strings.Join(export.Formats, "|"))
+	cmd.Flags().StringVar(&out, "out", "", "Write to this file instead of stdout")
+	cmd.Flags().StringVar(&since, "since", "", "Only messages on or after this date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().StringVar(&until, "until", "", "Only messages before this date (YYYY-MM-DD or RFC3339)")
+
+	return cmd
+}
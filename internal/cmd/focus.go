@@ -15,6 +15,11 @@ func newFocusCmd() *cobra.Command {
 		messageID  string
 		draftText  string
 		attachment string
+		tmplName   string
+		vars       []string
+		timeout    time.Duration
+		retries    int
+		settle     time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -24,7 +29,19 @@ func newFocusCmd() *cobra.Command {
 
 You can specify the chat by ID or by name:
   beeper focus --chat "Kishan" --draft "Hello!"
-  beeper focus --chat "!abc123:beeper.com"`,
+  beeper focus --chat "!abc123:beeper.com"
+
+--template fills the draft from a saved template rendered for the chat
+(see 'beeper templates'):
+  beeper focus --chat "Team" --template standup --var today="Ship it"
+
+With a draft, the chat is opened before the draft is set. Beeper's focus API
+does not normally report which chat is open, so the switch cannot be
+confirmed: after Beeper accepts navigation, the command waits --settle,
+navigates again, and then sets the draft. If Beeper does name the open chat,
+the draft waits until it names the requested one. Failed navigation is
+retried up to --retries times, and the command fails without setting the
+draft if navigation does not succeed within --timeout.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := getClient()
 			if err != nil {
@@ -41,38 +58,26 @@ You can specify the chat by ID or by name:
 				chatID = resolved
 			}
 
-			// If we have both chatID and draftText, use two-step approach
-			// to avoid draft being applied to wrong chat
-			if chatID != "" && draftText != "" {
-				// Step 1: Focus on chat first (no draft)
-				focusBody := api.FocusRequest{
-					ChatID:    chatID,
-					MessageID: messageID,
-				}
-				resp, err := client.Post(cmd.Context(), "/v1/focus", focusBody)
+			if tmplName != "" {
+				rendered, err := renderTemplate(cmd, client, tmplName, chatID, vars)
 				if err != nil {
-					return api.UserFriendlyError(err)
-				}
-				_ = resp.Body.Close()
-				if err := api.ParseErrorWithContext(resp, ""); err != nil {
 					return err
 				}
+				draftText = rendered
+			} else if len(vars) > 0 {
+				return fmt.Errorf("--var needs --template")
+			}
 
-				// Brief pause to let Beeper switch chats
-				time.Sleep(100 * time.Millisecond)
-
-				// Step 2: Now set the draft
-				draftBody := api.FocusRequest{
+			// With a chat and a draft, navigate first and give Beeper time to
+			// switch before applying the draft.
+			if chatID != "" && (draftText != "" || attachment != "") {
+				err := client.FocusChat(cmd.Context(), api.FocusRequest{
 					ChatID:              chatID,
+					MessageID:           messageID,
 					DraftText:           draftText,
 					DraftAttachmentPath: attachment,
-				}
-				resp, err = client.Post(cmd.Context(), "/v1/focus", draftBody)
+				}, api.FocusOptions{Timeout: timeout, Retries: retries, Settle: settle})
 				if err != nil {
-					return api.UserFriendlyError(err)
-				}
-				defer func() { _ = resp.Body.Close() }()
-				if err := api.ParseErrorWithContext(resp, ""); err != nil {
 					return err
 				}
 
@@ -88,13 +93,7 @@ You can specify the chat by ID or by name:
 				DraftAttachmentPath: attachment,
 			}
 
-			resp, err := client.Post(cmd.Context(), "/v1/focus", body)
-			if err != nil {
-				return api.UserFriendlyError(err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if err := api.ParseErrorWithContext(resp, ""); err != nil {
+			if err := client.Focus(cmd.Context(), body); err != nil {
 				return err
 			}
 
@@ -111,6 +110,12 @@ You can specify the chat by ID or by name:
 	cmd.Flags().StringVar(&messageID, "message", "", "Navigate to specific message")
 	cmd.Flags().StringVar(&draftText, "draft", "", "Pre-fill draft text")
 	cmd.Flags().StringVar(&attachment, "attachment", "", "Pre-fill draft attachment path")
+	cmd.Flags().StringVar(&tmplName, "template", "", "Pre-fill the draft from a saved template")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable key=value (repeatable)")
+	cmd.Flags().DurationVar(&timeout, "timeout", api.DefaultFocusTimeout, "Time limit for navigating to the chat before setting a draft")
+	cmd.Flags().IntVar(&retries, "retries", api.DefaultFocusRetries, "Retries for failed navigation before setting a draft")
+	cmd.Flags().DurationVar(&settle, "settle", api.DefaultFocusSettle, "Timed wait for Beeper to switch chats (the switch is not confirmed)")
+	cmd.MarkFlagsMutuallyExclusive("draft", "template")
 
 	return cmd
 }
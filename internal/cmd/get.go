@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+)
+
+func newMessagesGetCmd() *cobra.Command {
+	var (
+		contextLines int
+		maxScan      int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get <chat> <message-id>",
+		Short: "Show one message, optionally with the messages around it",
+		Long: `Show one message by ID. The chat can be given by ID or name.
+
+With --context N, also show the N messages before and after it, like
+grep -C. The message is marked with '>':
+  beeper messages get "Team" <message-id> --context 5
+
+There is no API to fetch a single message, so the chat's history is read
+newest first until the message is found, up to --max-scan messages. With
+-o json the result is {"message", "chat", "before", "after"}.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			chatID, err := resolveChatRef(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			chat, err := client.Chats().Get(cmd.Context(), chatID)
+			if err != nil {
+				return err
+			}
+
+			m, err := client.Messages().Find(cmd.Context(), chatID, args[1], maxScan)
+			if err != nil {
+				return err
+			}
+			mc, err := client.Messages().Context(cmd.Context(), *m, contextLines)
+			if err != nil {
+				return err
+			}
+			mc.Chat = chat
+
+			senders := api.NewSenders(client.Chats())
+			senders.Add(chat)
+			colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(cmd.Context()))
+			return outfmt.Output(cmd.Context(), mc, func(w io.Writer) {
+				if contextLines > 0 {
+					printMessageContext(cmd.Context(), w, senders, mc, colorEnabled)
+					return
+				}
+				_, _ = fmt.Fprintf(w, "ID:      %s\n", m.ID)
+				_, _ = fmt.Fprintf(w, "Chat:    %s\n", chat.Title)
+				_, _ = fmt.Fprintf(w, "Sender:  %s\n", senders.Name(cmd.Context(), *m, chat))
+				_, _ = fmt.Fprintf(w, "Time:    %s\n", formatTime(m.Timestamp))
+				if m.ReplyTo != nil {
+					_, _ = fmt.Fprintf(w, "Reply:   %s\n", m.ReplyTo.ID)
+				}
+				for _, a := range m.Attachments {
+					_, _ = fmt.Fprintf(w, "File:    %s (%s)\n", a.FileName, api.FormatSize(a.FileSize))
+				}
+				_, _ = fmt.Fprintf(w, "\n%s\n", m.Text)
+			})
+		},
+	}
+
+	cmd.Flags().IntVarP(&contextLines, "context", "C", 0, "Show this many messages before and after")
+	cmd.Flags().IntVar(&maxScan, "max-scan", api.DefaultFindScan, "Most messages to read while looking for the message")
+
+	return cmd
+}
+
+// printMessageContext prints a message between its neighbours, grep -C
+// style: a header naming the chat, then one line per message with the
+// message itself marked '>'.
+func printMessageContext(ctx context.Context, w io.Writer, senders *api.Senders, mc *api.MessageContext, color bool) {
+	title := mc.Message.ChatID
+	if mc.Chat != nil && mc.Chat.Title != "" {
+		title = mc.Chat.Title
+	}
+	_, _ = fmt.Fprintln(w, outfmt.Colorize("── "+title+" ──", outfmt.Gray, color))
+
+	line := func(m api.Message, mark string) {
+		text := strings.ReplaceAll(m.Text, "\n", " ")
+		if text == "" && len(m.Attachments) > 0 {
+			text = "[" + m.Attachments[0].FileName + "]"
+		}
+		sender := senders.Name(ctx, m, mc.Chat)
+		out := fmt.Sprintf("%s [%s] %s: %s", mark, formatTime(m.Timestamp), sender, text)
+		if mark == ">" {
+			out = outfmt.Colorize(out, outfmt.Bold, color)
+		}
+		_, _ = fmt.Fprintln(w, out)
+	}
+	for _, m := range mc.Before {
+		line(m, " ")
+	}
+	line(mc.Message, ">")
+	for _, m := range mc.After {
+		line(m, " ")
+	}
+}
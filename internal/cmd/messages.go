@@ -1,16 +1,21 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/url"
+	"iter"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/compose"
 	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+	"github.com/salmonumbrella/beeper-cli/internal/thread"
 )
 
 func newMessagesCmd() *cobra.Command {
@@ -20,8 +25,12 @@ func newMessagesCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(newMessagesListCmd())
+	cmd.AddCommand(newMessagesGetCmd())
 	cmd.AddCommand(newMessagesSearchCmd())
 	cmd.AddCommand(newMessagesSendCmd())
+	cmd.AddCommand(newMessagesTailCmd())
+	cmd.AddCommand(newMessagesBroadcastCmd())
+	cmd.AddCommand(newMessagesThreadCmd())
 
 	return cmd
 }
@@ -32,6 +41,9 @@ func newMessagesListCmd() *cobra.Command {
 		direction string
 		limit     int
 		chat      string
+		all       bool
+		max       int
+		flat      bool
 	)
 
 	cmd := &cobra.Command{
@@ -41,7 +53,16 @@ func newMessagesListCmd() *cobra.Command {
 
 You can specify the chat either by ID or by using --chat with a name:
   beeper messages list <chat-id>
-  beeper messages list --chat "Kishan"`,
+  beeper messages list --chat "Kishan"
+
+Text output is threaded: replies are indented under the message they answer
+(or quote it when it is older than the page), consecutive messages from one
+sender share a header, and each day starts with a separator. Use --flat for
+one "[time] sender: text" line per message.
+
+Use --all to follow pagination through the whole history, streaming messages
+as each page arrives (one JSON object per line with -o json):
+  beeper messages list --chat "Kishan" --all -o json > history.ndjson`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := getClient()
@@ -63,66 +84,63 @@ You can specify the chat either by ID or by using --chat with a name:
 				return fmt.Errorf("either <chat-id> argument or --chat flag is required")
 			}
 
-			params := url.Values{}
-			if cursor != "" {
-				params.Set("cursor", cursor)
-			}
-			if direction != "" {
-				params.Set("direction", direction)
-			}
-
-			// Fetch chat info first to get the other person's name
-			chatResp, err := client.Get(cmd.Context(), "/v1/chats/"+url.PathEscape(chatID))
+			// Fetch chat info first for participant names. Messages can still
+			// be listed when Beeper won't return the chat itself.
+			chat, err := client.Chats().Get(cmd.Context(), chatID)
 			if err != nil {
-				return api.UserFriendlyError(err)
-			}
-			var chat api.Chat
-			if chatResp.StatusCode == 200 {
-				_ = json.NewDecoder(chatResp.Body).Decode(&chat)
+				var apiErr *api.APIError
+				if !errors.As(err, &apiErr) || api.IsUnauthorized(err) {
+					return err
+				}
+				chat = &api.Chat{}
 			}
-			_ = chatResp.Body.Close()
 
-			path := "/v1/chats/" + url.PathEscape(chatID) + "/messages"
-			if len(params) > 0 {
-				path += "?" + params.Encode()
+			params := api.ListMessagesParams{
+				Cursor:    cursor,
+				Direction: direction,
 			}
 
-			resp, err := client.Get(cmd.Context(), path)
-			if err != nil {
-				return api.UserFriendlyError(err)
+			if all {
+				count := 0
+				for m, err := range client.Messages().All(cmd.Context(), chatID, params, max) {
+					if err != nil {
+						return err
+					}
+					count++
+					if err := outfmt.Output(cmd.Context(), m, func(w io.Writer) {
+						_, _ = fmt.Fprintf(w, "[%s] %s: %s\n", formatTime(m.Timestamp), api.SenderName(m, chat), m.Text)
+					}); err != nil {
+						return err
+					}
+				}
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%d messages\n", count)
+				return nil
 			}
-			defer func() { _ = resp.Body.Close() }()
 
-			if err := api.ParseErrorWithContext(resp, "Chat"); err != nil {
+			result, err := client.Messages().List(cmd.Context(), chatID, params)
+			if err != nil {
 				return err
 			}
 
-			var result api.ListMessagesResponse
-			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
-			}
-
 			messages := result.Items
 			if limit > 0 && len(messages) > limit {
 				messages = messages[:limit]
 			}
 
-			// Use chat title as the other person's name for DMs
-			otherName := chat.Title
-			if otherName == "" {
-				otherName = "Them"
-			}
-
+			colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(cmd.Context()))
 			return outfmt.Output(cmd.Context(), result, func(w io.Writer) {
-				for _, m := range messages {
-					sender := senderName(m.SenderID)
-					if sender == "Them" {
-						sender = otherName
+				if flat {
+					for _, m := range messages {
+						_, _ = fmt.Fprintf(w, "[%s] %s: %s\n", formatTime(m.Timestamp), api.SenderName(m, chat), m.Text)
 					}
-					_, _ = fmt.Fprintf(w, "[%s] %s: %s\n", formatTime(m.Timestamp), sender, m.Text)
+				} else {
+					thread.Render(w, messages, thread.Options{
+						Sender: func(m api.Message) string { return api.SenderName(m, chat) },
+						Color:  colorEnabled,
+					})
 				}
 				if result.HasMore {
-					_, _ = fmt.Fprintf(w, "\n(more messages available, use --cursor %s)\n", result.Cursor)
+					_, _ = fmt.Fprintf(w, "\n(more messages available, use --cursor %s or --all)\n", result.Cursor)
 				}
 			})
 		},
@@ -132,58 +150,97 @@ You can specify the chat either by ID or by using --chat with a name:
 	cmd.Flags().StringVar(&direction, "direction", "", "Direction: before or after")
 	cmd.Flags().IntVar(&limit, "limit", 0, "Limit number of messages")
 	cmd.Flags().StringVar(&chat, "chat", "", "Specify chat by name (searches for matching chat)")
+	cmd.Flags().BoolVar(&all, "all", false, "Follow pagination and stream every message")
+	cmd.Flags().IntVar(&max, "max", 0, "With --all, stop after this many messages (0 = no limit)")
+	cmd.Flags().BoolVar(&flat, "flat", false, "One line per message instead of threaded output")
 
 	return cmd
 }
 
 func newMessagesSearchCmd() *cobra.Command {
 	var (
-		chatIDs   string
-		dateAfter string
-		limit     int
+		chatIDs      string
+		dateAfter    string
+		dateBefore   string
+		networks     string
+		filter       api.MessageFilter
+		limit        int
+		all          bool
+		max          int
+		maxScan      int
+		contextLines int
 	)
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search messages",
-		Args:  cobra.ExactArgs(1),
+		Long: `Search messages across chats.
+
+Dates accept absolute and relative forms: 2024-01-31, 3d, 2w, yesterday,
+"last monday", "last month" or 2024-Q3. --network takes network names such
+as whatsapp and searches the accounts on them, instead of --account.
+
+--before, --sender, --from-me, --from-others, --has-link and --has-media are
+not supported by the search API, so they are applied here while paging
+through results: up to --limit matches (default 50) after reading at most
+--max-scan results, or every match with --all. The output notes which
+filters ran locally.
+
+With --context N, every hit is shown with the N messages before and after
+it, like grep -C, and blocks are separated by "--". With -o json each hit is
+printed on its own line as {"message", "chat", "before", "after"}:
+  beeper messages search "invoice" --context 3
+  beeper messages search "" --from-others --has-link --after "last monday"`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			query := args[0]
 
+			after, err := parseDateFlag("after", dateAfter)
+			if err != nil {
+				return err
+			}
+			if filter.Before, err = parseDateFlag("before", dateBefore); err != nil {
+				return err
+			}
+
 			client, err := getClient()
 			if err != nil {
 				return err
 			}
 
-			params := url.Values{}
-			params.Set("query", query)
-			if flags.Account != "" {
-				for _, id := range strings.Split(flags.Account, ",") {
-					params.Add("accountIDs", strings.TrimSpace(id))
-				}
+			params := api.SearchMessagesParams{
+				Query:      query,
+				AccountIDs: accountIDs(),
+				ChatIDs:    splitList(chatIDs),
 			}
-			if chatIDs != "" {
-				for _, id := range strings.Split(chatIDs, ",") {
-					params.Add("chatIDs", strings.TrimSpace(id))
-				}
+			if !after.IsZero() {
+				params.DateAfter = after.Format(time.RFC3339)
 			}
-			if dateAfter != "" {
-				params.Set("dateAfter", dateAfter)
+			if networks != "" {
+				if params.AccountIDs, err = networkAccountIDs(cmd.Context(), client, splitList(networks)); err != nil {
+					return err
+				}
 			}
 
-			resp, err := client.Get(cmd.Context(), "/v1/messages/search?"+params.Encode())
-			if err != nil {
-				return api.UserFriendlyError(err)
+			if len(filter.Local()) > 0 {
+				lim := searchLimits{all: all, max: max, limit: limit, maxScan: maxScan, contextLines: contextLines}
+				if all {
+					lim.maxScan = 0
+				}
+				return searchFiltered(cmd, client, params, filter, lim)
 			}
-			defer func() { _ = resp.Body.Close() }()
 
-			if err := api.ParseErrorWithContext(resp, ""); err != nil {
-				return err
+			if all {
+				hits := client.Messages().SearchAll(cmd.Context(), params, max)
+				if contextLines > 0 {
+					return streamSearchContexts(cmd, client, hits, contextLines)
+				}
+				return streamSearchHits(cmd, client, hits)
 			}
 
-			var result api.SearchMessagesResponse
-			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
+			result, err := client.Messages().Search(cmd.Context(), params)
+			if err != nil {
+				return err
 			}
 
 			messages := result.Messages
@@ -191,41 +248,243 @@ func newMessagesSearchCmd() *cobra.Command {
 				messages = messages[:limit]
 			}
 
-			return outfmt.Output(cmd.Context(), result, func(w io.Writer) {
-				tw := outfmt.NewTableWriter(w)
-				tw.SetHeader([]string{"Chat", "Sender", "Time", "Message"})
-				for _, m := range messages {
-					chatName := m.ChatID
-					if chat, ok := result.Chats[m.ChatID]; ok {
-						chatName = chat.Title
-					}
-					tw.Append([]string{
-						truncate(chatName, 20),
-						truncate(m.Sender, 15),
-						formatTime(m.Timestamp),
-						truncate(m.Text, 40),
-					})
-				}
-				tw.Render()
-				if result.HasMore {
-					_, _ = fmt.Fprintf(w, "\n(%d+ results, showing first page)\n", len(messages))
-				}
-			})
+			if contextLines > 0 {
+				return streamSearchContexts(cmd, client, responseHits(result, messages), contextLines)
+			}
+
+			return printSearchResult(cmd, client, searchOutput{SearchMessagesResponse: result}, messages)
 		},
 	}
 
 	cmd.Flags().StringVar(&chatIDs, "chat", "", "Filter by chat ID(s), comma-separated")
-	cmd.Flags().StringVar(&dateAfter, "after", "", "Messages after date (ISO format)")
+	cmd.Flags().StringVar(&dateAfter, "after", "", "Messages on or after date (2024-01-31, 3d, \"last monday\", 2024-Q3...)")
+	cmd.Flags().StringVar(&dateBefore, "before", "", "Messages before date (same forms as --after)")
+	cmd.Flags().StringVar(&filter.Sender, "sender", "", "Only messages whose sender name or ID contains this")
+	cmd.Flags().BoolVar(&filter.FromMe, "from-me", false, "Only messages you sent")
+	cmd.Flags().BoolVar(&filter.FromOthers, "from-others", false, "Only messages others sent")
+	cmd.Flags().StringVar(&networks, "network", "", "Only these networks (e.g. whatsapp,signal), comma-separated")
+	cmd.Flags().BoolVar(&filter.HasLink, "has-link", false, "Only messages containing a link")
+	cmd.Flags().BoolVar(&filter.HasMedia, "has-media", false, "Only messages with attachments")
 	cmd.Flags().IntVar(&limit, "limit", 0, "Limit number of results")
+	cmd.Flags().BoolVar(&all, "all", false, "Follow pagination and stream every result")
+	cmd.Flags().IntVar(&max, "max", 0, "With --all, stop after this many results (0 = no limit)")
+	cmd.Flags().IntVar(&maxScan, "max-scan", defaultSearchScan, "With local filters, read at most this many results (0 = no limit)")
+	cmd.Flags().IntVarP(&contextLines, "context", "C", 0, "Show this many messages before and after each hit")
+	cmd.MarkFlagsMutuallyExclusive("from-me", "from-others")
 
 	return cmd
 }
 
+// Local filtering defaults: how many raw results to read, and how many
+// matches to keep without --limit.
+const (
+	defaultSearchScan  = 2000
+	defaultSearchLimit = 50
+)
+
+// searchOutput is the JSON shape of a search page. LocalFilters and Scanned
+// are only set when filters ran on the client.
+type searchOutput struct {
+	*api.SearchMessagesResponse
+	LocalFilters []string `json:"localFilters,omitempty"`
+	Scanned      int      `json:"scanned,omitempty"`
+}
+
+// searchLimits bounds searchFiltered. With all, matches are streamed up to
+// max; otherwise up to limit are printed as one page.
+type searchLimits struct {
+	all          bool
+	max          int
+	limit        int
+	maxScan      int // raw results read; 0 = no limit
+	contextLines int
+}
+
+// searchFiltered pages through search results applying filter on the client,
+// then prints the matches like an unfiltered search and notes which filters
+// ran locally.
+func searchFiltered(cmd *cobra.Command, client *api.Client, params api.SearchMessagesParams, filter api.MessageFilter, lim searchLimits) error {
+	ctx := cmd.Context()
+	local := filter.Local()
+	senders := api.NewSenders(client.Chats())
+	scanned := 0
+
+	if lim.all {
+		hits := filter.Apply(ctx, client.Messages().SearchAll(ctx, params, 0), senders, lim.max, lim.maxScan, &scanned)
+		var err error
+		if lim.contextLines > 0 {
+			err = streamSearchContexts(cmd, client, hits, lim.contextLines)
+		} else {
+			err = streamSearchHits(cmd, client, hits)
+		}
+		if err == nil {
+			note := fmt.Sprintf("filtered locally: %s (%d results read", strings.Join(local, ", "), scanned)
+			if lim.maxScan > 0 && scanned >= lim.maxScan {
+				note += fmt.Sprintf(", stopped at %d", lim.maxScan)
+			}
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), note+")")
+		}
+		return err
+	}
+
+	limit := lim.limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	out := searchOutput{
+		SearchMessagesResponse: &api.SearchMessagesResponse{Chats: map[string]api.Chat{}},
+		LocalFilters:           local,
+	}
+	for hit, err := range filter.Apply(ctx, client.Messages().SearchAll(ctx, params, 0), senders, limit, lim.maxScan, &scanned) {
+		if err != nil {
+			return err
+		}
+		out.Messages = append(out.Messages, hit.Message)
+		if hit.Chat != nil {
+			out.Chats[hit.ChatID] = *hit.Chat
+		}
+	}
+	out.Scanned = scanned
+	out.HasMore = len(out.Messages) >= limit || lim.maxScan > 0 && scanned >= lim.maxScan
+
+	note := fmt.Sprintf("filtered locally: %s; %d of %d results read matched", strings.Join(local, ", "), len(out.Messages), scanned)
+	if lim.maxScan > 0 && scanned >= lim.maxScan && len(out.Messages) < limit {
+		note += fmt.Sprintf("; stopped at --max-scan %d", lim.maxScan)
+	}
+
+	if lim.contextLines > 0 {
+		if err := streamSearchContexts(cmd, client, responseHits(out.SearchMessagesResponse, out.Messages), lim.contextLines); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), note)
+		return nil
+	}
+	return printSearchResult(cmd, client, out, out.Messages, note)
+}
+
+// responseHits pairs messages from a search page with their chats.
+func responseHits(result *api.SearchMessagesResponse, messages []api.Message) iter.Seq2[api.SearchHit, error] {
+	return func(yield func(api.SearchHit, error) bool) {
+		for _, m := range messages {
+			hit := api.SearchHit{Message: m}
+			if c, ok := result.Chats[m.ChatID]; ok {
+				hit.Chat = &c
+			}
+			if !yield(hit, nil) {
+				return
+			}
+		}
+	}
+}
+
+// printSearchResult prints one page of search results as a table, followed
+// by any notes.
+func printSearchResult(cmd *cobra.Command, client *api.Client, out searchOutput, messages []api.Message, notes ...string) error {
+	senders := api.NewSenders(client.Chats())
+	return outfmt.Output(cmd.Context(), out, func(w io.Writer) {
+		tw := outfmt.NewTableWriter(w)
+		tw.SetHeader([]string{"Chat", "Sender", "Time", "Message"})
+		for _, m := range messages {
+			chatName := m.ChatID
+			var chat *api.Chat
+			if c, ok := out.Chats[m.ChatID]; ok {
+				chatName = c.Title
+				chat = &c
+			}
+			tw.Append([]string{
+				truncate(chatName, 20),
+				truncate(senders.Name(cmd.Context(), m, chat), 15),
+				formatTime(m.Timestamp),
+				truncate(m.Text, 40),
+			})
+		}
+		tw.Render()
+		if out.HasMore && out.LocalFilters == nil {
+			_, _ = fmt.Fprintf(w, "\n(%d+ results, showing first page; use --all for more)\n", len(messages))
+		}
+		for _, note := range notes {
+			_, _ = fmt.Fprintf(w, "\n(%s)\n", note)
+		}
+	})
+}
+
+// streamSearchContexts prints each hit with n messages around it as it is
+// fetched: grep -C style blocks in text mode, or one JSON object per line.
+func streamSearchContexts(cmd *cobra.Command, client *api.Client, hits iter.Seq2[api.SearchHit, error], n int) error {
+	ctx := cmd.Context()
+	senders := api.NewSenders(client.Chats())
+	colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(ctx))
+
+	count := 0
+	for hit, err := range hits {
+		if err != nil {
+			return err
+		}
+		mc, err := client.Messages().Context(ctx, hit.Message, n)
+		if err != nil {
+			return fmt.Errorf("failed to load context for message %s: %w", hit.ID, err)
+		}
+		mc.Chat = hit.Chat
+		if err := outfmt.Output(ctx, mc, func(w io.Writer) {
+			if count > 0 {
+				_, _ = fmt.Fprintln(w, "--")
+			}
+			printMessageContext(ctx, w, senders, mc, colorEnabled)
+		}); err != nil {
+			return err
+		}
+		count++
+	}
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%d results\n", count)
+	return nil
+}
+
+// streamSearchHits prints search results as pages arrive: one JSON object per
+// line in JSON mode, or fixed-width table rows in text mode.
+func streamSearchHits(cmd *cobra.Command, client *api.Client, hits iter.Seq2[api.SearchHit, error]) error {
+	ctx := cmd.Context()
+	senders := api.NewSenders(client.Chats())
+
+	var st *outfmt.StreamTable
+	if outfmt.GetFormat(ctx) != "json" {
+		st = outfmt.NewStreamTable(os.Stdout, 20, 15, 16)
+		st.Row("Chat", "Sender", "Time", "Message")
+	}
+
+	count := 0
+	for hit, err := range hits {
+		if err != nil {
+			return err
+		}
+		count++
+		if err := outfmt.Output(ctx, hit, func(io.Writer) {
+			chatName := hit.ChatID
+			if hit.Chat != nil {
+				chatName = hit.Chat.Title
+			}
+			st.Row(truncate(chatName, 20), truncate(senders.Name(ctx, hit.Message, hit.Chat), 15), formatTime(hit.Timestamp), truncate(hit.Text, 40))
+		}); err != nil {
+			return err
+		}
+	}
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%d results\n", count)
+	return nil
+}
+
 func newMessagesSendCmd() *cobra.Command {
 	var (
-		text    string
-		replyTo string
-		to      string
+		text     string
+		replyTo  string
+		to       string
+		files    []string
+		maxSize  int64
+		noDraft  bool
+		textFile string
+		edit     bool
+		tmplName string
+		vars     []string
 	)
 
 	cmd := &cobra.Command{
@@ -237,11 +496,51 @@ You can specify the chat either by ID or by using --to with a name:
   beeper messages send <chat-id> --text "Hello"
   beeper messages send --to "Kishan" --text "Hello"
 
-Using --to searches for a chat by name and uses the first match.`,
+Using --to searches for a chat by name and uses the first match.
+
+Attach files with --file (repeatable). Each file is sent as its own message,
+with --text as the caption of the first:
+  beeper messages send --to "Kishan" --file report.pdf --text "Q3 numbers"
+  beeper messages send <chat-id> --file a.jpg --file b.jpg
+
+If this Beeper Desktop version can't upload files, a single file is placed
+in a draft in the chat instead (with the caption as draft text), for you to
+send from the app. A draft can't be a reply, so with --reply-to the command
+fails instead. Use --no-draft-fallback to always fail instead.
+
+Multi-line messages can come from stdin, a file or your editor:
+  git log -1 --format=%B | beeper messages send --to "Team" --text -
+  beeper messages send --to "Team" --text-file notes.txt
+  beeper messages send --to "Team" --edit
+
+--edit opens $VISUAL or $EDITOR with the chat's recent messages shown as
+comments, like git commit. Lines starting with '#' are dropped, and an empty
+message aborts the send. Combine with --text to start from a draft.
+
+--template renders a saved template (see 'beeper templates') for the chat,
+with --var supplying its variables. Combine with --edit to review it first:
+  beeper messages send --to "Team" --template standup --var today="Ship it" --edit`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if text == "" {
-				return fmt.Errorf("--text is required")
+			var err error
+			if text, err = readMessageText(text, textFile); err != nil {
+				return err
+			}
+			if text == "" && len(files) == 0 && !edit && tmplName == "" {
+				return fmt.Errorf("--text, --text-file, --template, --edit or --file is required")
+			}
+			if len(vars) > 0 && tmplName == "" {
+				return fmt.Errorf("--var needs --template")
+			}
+
+			// Check attachments before contacting the API.
+			attachments := make([]*api.File, 0, len(files))
+			for _, path := range files {
+				f, err := api.OpenFile(path, maxSize)
+				if err != nil {
+					return err
+				}
+				attachments = append(attachments, f)
 			}
 
 			client, err := getClient()
@@ -263,73 +562,203 @@ Using --to searches for a chat by name and uses the first match.`,
 				return fmt.Errorf("either <chat-id> argument or --to flag is required")
 			}
 
+			if tmplName != "" {
+				rendered, err := renderTemplate(cmd, client, tmplName, chatID, vars)
+				if err != nil {
+					return err
+				}
+				text = rendered
+			}
+
+			if edit {
+				edited, err := editMessage(cmd, client, chatID, text)
+				if err != nil {
+					return err
+				}
+				text = edited
+			}
+
 			body := api.SendMessageRequest{
 				Text:             text,
 				ReplyToMessageID: replyTo,
 			}
 
-			resp, err := client.Post(cmd.Context(), "/v1/chats/"+url.PathEscape(chatID)+"/messages", body)
-			if err != nil {
-				return api.UserFriendlyError(err)
+			if len(attachments) > 0 {
+				return sendAttachments(cmd, client, chatID, body, attachments, !noDraft)
 			}
-			defer func() { _ = resp.Body.Close() }()
 
-			if err := api.ParseErrorWithContext(resp, "Chat"); err != nil {
+			result, err := client.Messages().Send(cmd.Context(), chatID, body)
+			if err != nil {
 				return err
 			}
 
-			var result api.SendMessageResponse
-			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
-			}
-
 			return outfmt.Output(cmd.Context(), result, func(w io.Writer) {
 				_, _ = fmt.Fprintf(w, "Message sent (ID: %s)\n", result.MessageID)
 			})
 		},
 	}
 
-	cmd.Flags().StringVar(&text, "text", "", "Message text (required)")
+	cmd.Flags().StringVar(&text, "text", "", "Message text, or the caption when sending files ('-' reads stdin)")
+	cmd.Flags().StringVar(&textFile, "text-file", "", "Read the message text from a file")
+	cmd.Flags().BoolVarP(&edit, "edit", "e", false, "Compose the message in $EDITOR")
+	cmd.Flags().StringVar(&tmplName, "template", "", "Render the message from a saved template")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable key=value (repeatable)")
 	cmd.Flags().StringVar(&replyTo, "reply-to", "", "Reply to message ID")
 	cmd.Flags().StringVar(&to, "to", "", "Send to chat by name (searches for matching chat)")
-	_ = cmd.MarkFlagRequired("text")
+	cmd.Flags().StringArrayVar(&files, "file", nil, "Attach a file (repeatable)")
+	cmd.Flags().Int64Var(&maxSize, "max-size", api.DefaultMaxUploadSize, "Largest file to send, in bytes")
+	cmd.Flags().BoolVar(&noDraft, "no-draft-fallback", false, "Fail instead of drafting when uploads are unsupported")
+	cmd.MarkFlagsMutuallyExclusive("text", "text-file", "template")
 
 	return cmd
 }
 
+// editContextLines is how many recent messages --edit shows as context.
+const editContextLines = 10
+
+// editMessage opens the user's editor, pre-filled with initial text and the
+// chat's latest messages as comments, and returns what they wrote.
+func editMessage(cmd *cobra.Command, client *api.Client, chatID, initial string) (string, error) {
+	ctx := cmd.Context()
+	title := chatID
+	var recent []compose.ContextLine
+
+	// Context is a convenience; compose without it if the lookups fail.
+	chat, err := client.Chats().Get(ctx, chatID)
+	if err == nil {
+		title = chat.Title
+		if page, err := client.Messages().List(ctx, chatID, api.ListMessagesParams{}); err == nil {
+			messages := page.Items
+			slices.SortStableFunc(messages, func(a, b api.Message) int { return a.Timestamp.Compare(b.Timestamp) })
+			if len(messages) > editContextLines {
+				messages = messages[len(messages)-editContextLines:]
+			}
+			for _, m := range messages {
+				recent = append(recent, compose.ContextLine{
+					Time:   formatTime(m.Timestamp),
+					Sender: api.SenderName(m, chat),
+					Text:   m.Text,
+				})
+			}
+		}
+	}
+
+	return compose.Edit(ctx, compose.Editor(), compose.Template(initial, title, recent))
+}
+
+// sendAttachments uploads and sends files, falling back to a focus draft
+// when Beeper Desktop has no upload endpoint.
+func sendAttachments(cmd *cobra.Command, client *api.Client, chatID string, body api.SendMessageRequest, files []*api.File, allowDraft bool) error {
+	results, err := client.Messages().SendFiles(cmd.Context(), chatID, body, files)
+	if errors.Is(err, api.ErrUploadUnsupported) {
+		if !allowDraft {
+			return err
+		}
+		if len(files) > 1 {
+			return fmt.Errorf("%w; a draft can hold only one file, send them one at a time", err)
+		}
+		if body.ReplyToMessageID != "" {
+			return fmt.Errorf("%w; a draft can't reply to a message, so nothing was sent (drop --reply-to to draft it)", err)
+		}
+		draft := api.FocusRequest{ChatID: chatID, DraftText: body.Text, DraftAttachmentPath: files[0].Path}
+		if err := client.Focus(cmd.Context(), draft); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Uploads aren't supported by this Beeper Desktop version; opened a draft with %s instead. Press send in Beeper to deliver it.\n", files[0].Name)
+		return outfmt.Output(cmd.Context(), map[string]any{"drafted": true, "file": files[0].Path}, func(w io.Writer) {
+			_, _ = fmt.Fprintf(w, "Draft created with %s\n", files[0].Name)
+		})
+	}
+	if err != nil {
+		if len(results) > 0 {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Sent %d of %d files before the error\n", len(results), len(files))
+		}
+		return err
+	}
+
+	return outfmt.Output(cmd.Context(), results, func(w io.Writer) {
+		for i, r := range results {
+			_, _ = fmt.Fprintf(w, "Sent %s (%s, %s) (ID: %s)\n", files[i].Name, files[i].MimeType, api.FormatSize(files[i].Size), r.MessageID)
+		}
+	})
+}
+
+// readMessageText returns the message text of --text, reading stdin for
+// "-", or of --text-file when given.
+func readMessageText(text, textFile string) (string, error) {
+	switch {
+	case text == "-":
+		if flags.TokenStdin {
+			return "", fmt.Errorf("--text - and --token-stdin both read stdin; pass the token another way")
+		}
+		return compose.Read(os.Stdin)
+	case textFile != "":
+		return compose.ReadFile(textFile)
+	}
+	return text, nil
+}
+
 // resolveChatByName searches for a chat by name and returns its ID.
 // If multiple matches are found, it returns the first one.
 // If no matches are found, it returns an error.
 func resolveChatByName(cmd *cobra.Command, client *api.Client, name string) (string, error) {
-	params := url.Values{}
-	params.Set("query", name)
-
-	resp, err := client.Get(cmd.Context(), "/v1/chats/search?"+params.Encode())
+	chat, err := findChatByName(cmd, client, name, false)
 	if err != nil {
-		return "", api.UserFriendlyError(err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if err := api.ParseErrorWithContext(resp, ""); err != nil {
 		return "", err
 	}
+	return chat.ID, nil
+}
 
-	var result api.ListChatsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+// findChatByName searches for a chat by name. When several chats match, a
+// lenient lookup takes the first; a strict one accepts only a single chat
+// whose title equals name (ignoring case) and otherwise lists the candidates.
+func findChatByName(cmd *cobra.Command, client *api.Client, name string, strict bool) (*api.Chat, error) {
+	result, err := client.Chats().Search(cmd.Context(), api.SearchChatsParams{Query: name})
+	if err != nil {
+		return nil, err
 	}
 
 	if len(result.Items) == 0 {
-		return "", fmt.Errorf("no chat found matching %q", name)
+		return nil, fmt.Errorf("no chat found matching %q", name)
 	}
 
 	chat := result.Items[0]
-	if len(result.Items) > 1 {
-		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Note: %d chats match %q, using %q (%s)\n",
-			len(result.Items), name, chat.Title, chat.Network)
+	if len(result.Items) == 1 {
+		return &chat, nil
 	}
 
-	return chat.ID, nil
+	if strict {
+		var exact []api.Chat
+		for _, c := range result.Items {
+			if strings.EqualFold(strings.TrimSpace(c.Title), strings.TrimSpace(name)) {
+				exact = append(exact, c)
+			}
+		}
+		if len(exact) == 1 {
+			return &exact[0], nil
+		}
+		candidates := result.Items
+		if len(exact) > 1 {
+			candidates = exact
+		}
+		lines := make([]string, 0, len(candidates))
+		for _, c := range candidates {
+			lines = append(lines, fmt.Sprintf("  %s (%s) %s", c.Title, c.Network, c.ID))
+		}
+		return nil, fmt.Errorf("%q matches %d chats; use a chat ID instead:\n%s", name, len(candidates), strings.Join(lines, "\n"))
+	}
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Note: %d chats match %q, using %q (%s)\n",
+		len(result.Items), name, chat.Title, chat.Network)
+	return &chat, nil
+}
+
+// resolveChatRef accepts either a chat ID or a chat name.
+func resolveChatRef(cmd *cobra.Command, client *api.Client, ref string) (string, error) {
+	if looksLikeChatID(ref) {
+		return ref, nil
+	}
+	return resolveChatByName(cmd, client, ref)
 }
 
 // looksLikeChatID returns true if the string looks like a chat ID rather than a name
@@ -337,18 +766,3 @@ func looksLikeChatID(s string) bool {
 	// Chat IDs typically contain special characters like ! : @ or ##
 	return strings.Contains(s, "!") || strings.Contains(s, ":") || strings.Contains(s, "@") || strings.Contains(s, "##")
 }
-
-// senderName returns a display name for a sender ID
-func senderName(senderID string) string {
-	if strings.Contains(senderID, ":beeper.com") {
-		return "You"
-	}
-	// Extract username from Matrix-style ID like @username:server
-	if strings.HasPrefix(senderID, "@") {
-		parts := strings.SplitN(senderID[1:], ":", 2)
-		if len(parts) > 0 && len(parts[0]) < 20 {
-			return parts[0]
-		}
-	}
-	return "Them"
-}
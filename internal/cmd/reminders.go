@@ -1,31 +1,153 @@
 package cmd
 
 import (
+	"cmp"
+	"context"
 	"fmt"
-	"net/url"
+	"io"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/ical"
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+	"github.com/salmonumbrella/beeper-cli/internal/schedule"
+	"github.com/salmonumbrella/beeper-cli/internal/timeexpr"
 )
 
 func newRemindersCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "reminders",
 		Short: "Manage chat reminders",
+		Long: `Set, snooze, clear and list chat reminders.
+
+Beeper holds one reminder per chat. Recurring reminders (reminders set
+--every) are kept in the data directory and re-armed after each occurrence
+by 'beeper reminders run', either as a long-running process or from cron.`,
 	}
 
+	cmd.AddCommand(newRemindersListCmd())
 	cmd.AddCommand(newRemindersSetCmd())
+	cmd.AddCommand(newRemindersSnoozeCmd())
 	cmd.AddCommand(newRemindersClearCmd())
+	cmd.AddCommand(newRemindersExportCmd())
+	cmd.AddCommand(newRemindersImportCmd())
+	cmd.AddCommand(newRemindersRecurringCmd())
+	cmd.AddCommand(newRemindersRunCmd())
+
+	return cmd
+}
+
+func newRemindersListCmd() *cobra.Command {
+	var (
+		dueBefore string
+		overdue   bool
+		inboxes   string
+		ics       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List reminders across all chats",
+		Long: `List every chat reminder, soonest first. Overdue reminders are marked.
+
+Every chat in the chosen inboxes is scanned, so this can take a moment on
+large accounts:
+  beeper reminders list
+  beeper reminders list --overdue
+  beeper reminders list --due-before tomorrow -o json
+  beeper reminders list --ics > reminders.ics`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			before, err := parseDateFlag("due-before", dueBefore)
+			if err != nil {
+				return err
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+
+			reminders, err := scanReminders(cmd, client, inboxes)
+			if err != nil {
+				return err
+			}
+			reminders = slices.DeleteFunc(reminders, func(r api.Reminder) bool {
+				return overdue && !r.Overdue || !before.IsZero() && !r.RemindAt.Before(before)
+			})
+
+			if ics {
+				return ical.Write(os.Stdout, ical.ReminderCalendar(reminders, time.Now()))
+			}
+
+			colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(cmd.Context()))
+			return outfmt.Output(cmd.Context(), reminders, func(w io.Writer) {
+				if len(reminders) == 0 {
+					_, _ = fmt.Fprintln(w, "No reminders")
+					return
+				}
+				tw := outfmt.NewTableWriter(w)
+				tw.SetHeader([]string{"Due", "Chat", "Network", "Chat ID"})
+				late := 0
+				for _, r := range reminders {
+					due := formatReminderTime(r.RemindAt)
+					if r.Overdue {
+						late++
+						due = outfmt.Colorize(due+" (overdue)", outfmt.Red, colorEnabled)
+					}
+					tw.Append([]string{due, truncate(r.Title, 30), r.Network, r.ChatID})
+				}
+				tw.Render()
+				_, _ = fmt.Fprintf(w, "\n%d reminders, %d overdue\n", len(reminders), late)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&dueBefore, "due-before", "", "Only reminders due before this date (2025-03-01, tomorrow, \"this week\"...)")
+	cmd.Flags().BoolVar(&overdue, "overdue", false, "Only reminders that are already due")
+	cmd.Flags().StringVar(&inboxes, "inbox", "", "Inboxes to scan, comma-separated (default: all)")
+	cmd.Flags().BoolVar(&ics, "ics", false, "Write the reminders as an iCalendar file")
 
 	return cmd
 }
 
+// scanReminders lists the reminders in the given inboxes (all when empty),
+// warning when the chat scan was cut short.
+func scanReminders(cmd *cobra.Command, client *api.Client, inboxes string) ([]api.Reminder, error) {
+	reminders, scan, err := client.Reminders().List(cmd.Context(), api.EnumerateParams{
+		Inboxes:    splitList(inboxes),
+		AccountIDs: accountIDs(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !scan.Complete() {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", scan.Summary())
+	}
+	return reminders, nil
+}
+
+// formatReminderTime shows a reminder's local date and time, with the year
+// only when it isn't the current one.
+func formatReminderTime(t time.Time) string {
+	local := t.Local()
+	if local.Year() == time.Now().Year() {
+		return local.Format("Mon Jan 2, 3:04 PM")
+	}
+	return local.Format("Mon Jan 2 2006, 3:04 PM")
+}
+
 func newRemindersSetCmd() *cobra.Command {
 	var (
-		at   string
-		chat string
+		at    string
+		tz    string
+		chat  string
+		every string
 	)
 
 	cmd := &cobra.Command{
@@ -35,20 +157,55 @@ func newRemindersSetCmd() *cobra.Command {
 
 You can specify the chat by ID or by using --chat with a name:
   beeper reminders set <chat-id> --at "2024-12-26 10:00"
-  beeper reminders set --chat "Kishan" --at "2024-12-26 10:00"`,
-		Args: cobra.MaximumNArgs(1),
+  beeper reminders set --chat "Kishan" --at "tomorrow 9am"
+
+--at accepts "in 2h", 17:30, 9am, "tomorrow 9am", "next fri", weekday names,
+eod (17:00), eow (17:00 Friday), "2025-01-02 09:30" or RFC 3339. Times are
+read in your local time zone, or in --tz (an IANA name such as Europe/Berlin).
+The resolved time is printed before the reminder is set.
+
+With --every the reminder repeats: daily, weekday, weekend or day names
+like mon,wed,fri, followed by a time of day (default 9:00):
+  beeper reminders set --chat "Team" --every weekday 9:00
+  beeper reminders set <chat-id> --every "mon,thu" --at 5pm --tz Europe/Berlin
+
+The first occurrence is set right away; 'beeper reminders run' sets each
+next one after the previous passes.`,
+		Args: cobra.MaximumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if at == "" {
-				return fmt.Errorf("--at is required")
+			// With --every, a trailing argument may be the time of day.
+			var clock string
+			if every != "" && (len(args) == 2 || len(args) == 1 && chat != "") {
+				clock, args = args[len(args)-1], args[:len(args)-1]
+				if at != "" {
+					return fmt.Errorf("give the time of day once, as an argument or with --at")
+				}
+			}
+			if len(args) > 1 {
+				return fmt.Errorf("accepts at most 1 chat, received %d arguments", len(args))
 			}
 
-			reminderTime, err := time.Parse(time.RFC3339, at)
-			if err != nil {
-				// Try simpler format
-				reminderTime, err = time.Parse("2006-01-02 15:04", at)
+			var rule schedule.Recurrence
+			var reminderTime time.Time
+			if every != "" {
+				r, err := schedule.ParseEvery(every, cmp.Or(clock, at), tz)
 				if err != nil {
-					return fmt.Errorf("invalid time format. Use: 2006-01-02 15:04 or RFC3339")
+					return fmt.Errorf("invalid --every: %w", err)
+				}
+				rule = r
+				reminderTime = rule.Next(time.Now())
+			} else {
+				if at == "" {
+					return fmt.Errorf("--at is required")
+				}
+				t, err := parseTimeFlag("at", at, tz)
+				if err != nil {
+					return err
+				}
+				if !t.After(time.Now()) {
+					return fmt.Errorf("--at %s is in the past", t.Format(absoluteLayout))
 				}
+				reminderTime = t
 			}
 
 			client, err := getClient()
@@ -70,25 +227,138 @@ You can specify the chat by ID or by using --chat with a name:
 				return fmt.Errorf("either <chat-id> argument or --chat flag is required")
 			}
 
-			body := api.ReminderRequest{Reminder: api.NewReminderTime(reminderTime)}
-			resp, err := client.Post(cmd.Context(), "/v1/chats/"+url.PathEscape(chatID)+"/reminders", body)
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Setting reminder for %s\n", formatAbsolute(reminderTime))
+			if err := client.Reminders().Set(cmd.Context(), chatID, reminderTime); err != nil {
+				return err
+			}
+
+			if every == "" {
+				fmt.Printf("Reminder set for %s\n", reminderTime.Format("Jan 2, 2006 at 3:04 PM MST"))
+				return nil
+			}
+
+			title := chatID
+			if c, err := client.Chats().Get(cmd.Context(), chatID); err == nil && c.Title != "" {
+				title = c.Title
+			}
+			store, err := openRecurring()
 			if err != nil {
-				return api.UserFriendlyError(err)
+				return err
 			}
-			defer func() { _ = resp.Body.Close() }()
+			item, err := store.Add(schedule.Recurring{ChatID: chatID, ChatTitle: title, Every: rule, Next: reminderTime})
+			if err != nil {
+				return err
+			}
+			return outfmt.Output(cmd.Context(), item, func(w io.Writer) {
+				_, _ = fmt.Fprintf(w, "Recurring reminder %s for %s: %s\n", item.ID, item.ChatTitle, item.Every)
+				_, _ = fmt.Fprintln(w, "Each next occurrence is set by 'beeper reminders run'.")
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&at, "at", "", "Reminder time (e.g. \"tomorrow 9am\", \"in 2h\", \"next fri\", 2024-12-25 10:00); with --every, the time of day")
+	cmd.Flags().StringVar(&tz, "tz", "", "Time zone for --at, e.g. Europe/Berlin (default: local)")
+	cmd.Flags().StringVar(&chat, "chat", "", "Specify chat by name (searches for matching chat)")
+	cmd.Flags().StringVar(&every, "every", "", "Repeat on these days: daily, weekday, weekend or mon,wed,fri (with an optional time)")
 
-			if err := api.ParseErrorWithContext(resp, "Chat"); err != nil {
+	return cmd
+}
+
+// openRecurring opens the recurring reminders file in the data directory.
+func openRecurring() (*schedule.RecurringStore, error) {
+	path, err := schedule.DefaultRecurringPath()
+	if err != nil {
+		return nil, err
+	}
+	return schedule.OpenRecurring(path), nil
+}
+
+func newRemindersSnoozeCmd() *cobra.Command {
+	var tz string
+
+	cmd := &cobra.Command{
+		Use:   "snooze <chat> [for]",
+		Short: "Move a chat's reminder later",
+		Long: `Move an existing reminder later, by 1h unless told otherwise.
+
+The delay counts from the reminder's current time, or from now when it is
+already overdue. An absolute time such as "tomorrow 9am" works too:
+  beeper reminders snooze "Kishan" 1h
+  beeper reminders snooze <chat-id> 2d
+  beeper reminders snooze <chat-id> "mon 9am"`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			by := "1h"
+			if len(args) == 2 {
+				by = args[1]
+			}
+			loc := time.Local
+			if tz != "" {
+				l, err := time.LoadLocation(tz)
+				if err != nil {
+					return fmt.Errorf("invalid --tz: %w", err)
+				}
+				loc = l
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			chatID, err := resolveChatRef(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			chat, err := client.Chats().Get(cmd.Context(), chatID)
+			if err != nil {
+				return err
+			}
+			if chat.ReminderAt == nil || chat.ReminderAt.IsZero() {
+				return fmt.Errorf("%s has no reminder to snooze; set one with 'beeper reminders set'", chat.Title)
+			}
+
+			now := time.Now()
+			base := *chat.ReminderAt
+			if base.Before(now) {
+				base = now
+			}
+			until, err := timeexpr.ParseTime("+"+strings.TrimPrefix(by, "+"), base, loc)
+			if err != nil {
+				if until, err = timeexpr.ParseTime(by, now, loc); err != nil {
+					return fmt.Errorf("invalid snooze %q. Use a delay like 1h, 30m or 2d, or a time like \"tomorrow 9am\"", by)
+				}
+			}
+			if !until.After(now) {
+				return fmt.Errorf("%s is in the past", until.Format(absoluteLayout))
+			}
+
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Snoozing reminder until %s\n", formatAbsolute(until))
+			if err := client.Reminders().Set(cmd.Context(), chatID, until); err != nil {
 				return err
 			}
 
-			fmt.Printf("Reminder set for %s\n", reminderTime.Format("Jan 2, 2006 at 3:04 PM"))
+			fmt.Printf("Reminder for %s snoozed until %s\n", chat.Title, until.Format("Jan 2, 2006 at 3:04 PM MST"))
+
+			// Keep a recurring rule from re-arming over the snoozed reminder.
+			store, err := openRecurring()
+			if err == nil {
+				err = store.Update(func(items []schedule.Recurring) ([]schedule.Recurring, error) {
+					for i := range items {
+						if items[i].ChatID == chatID {
+							items[i].Next = until
+						}
+					}
+					return items, nil
+				})
+			}
+			if err != nil {
+				return fmt.Errorf("failed to record the snooze for recurring reminders, so 'beeper reminders run' may set it earlier: %w", err)
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&at, "at", "", "Reminder time (required, e.g., '2024-12-25 10:00' or RFC3339)")
-	cmd.Flags().StringVar(&chat, "chat", "", "Specify chat by name (searches for matching chat)")
-	_ = cmd.MarkFlagRequired("at")
+	cmd.Flags().StringVar(&tz, "tz", "", "Time zone for an absolute time, e.g. Europe/Berlin (default: local)")
 
 	return cmd
 }
@@ -125,22 +395,376 @@ You can specify the chat by ID or by using --chat with a name:
 				return fmt.Errorf("either <chat-id> argument or --chat flag is required")
 			}
 
-			resp, err := client.Delete(cmd.Context(), "/v1/chats/"+url.PathEscape(chatID)+"/reminders")
+			if err := client.Reminders().Clear(cmd.Context(), chatID); err != nil {
+				return err
+			}
+
+			fmt.Println("Reminder cleared")
+
+			// A recurring rule would set it again on the next run.
+			store, err := openRecurring()
+			if err != nil {
+				return err
+			}
+			return store.Update(func(items []schedule.Recurring) ([]schedule.Recurring, error) {
+				return slices.DeleteFunc(items, func(r schedule.Recurring) bool {
+					if r.ChatID == chatID {
+						fmt.Printf("Stopped recurring reminder %s (%s)\n", r.ID, r.Every)
+						return true
+					}
+					return false
+				}), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&chat, "chat", "", "Specify chat by name (searches for matching chat)")
+
+	return cmd
+}
+
+func newRemindersExportCmd() *cobra.Command {
+	var (
+		out     string
+		inboxes string
+		ics     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export reminders as an iCalendar file",
+		Long: `Write every chat reminder as a VEVENT in an iCalendar (.ics) file, for
+importing into a calendar app. Each event names the chat and its network
+and links to the chat in Beeper. iCalendar is the only format, so --ics is
+optional and --ics=false is rejected:
+  beeper reminders export --ics > reminders.ics
+  beeper reminders export --out ~/reminders.ics
+
+The events carry the chat ID, so 'beeper reminders import' can set the
+reminders again after they are moved in the calendar.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !ics {
+				return fmt.Errorf("iCalendar is the only export format; --ics=false is not supported")
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			reminders, err := scanReminders(cmd, client, inboxes)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			dest := "stdout"
+			if out != "" && out != "-" {
+				f, err := os.Create(out)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", out, err)
+				}
+				defer func() { _ = f.Close() }()
+				w, dest = f, out
+			}
+			if err := ical.Write(w, ical.ReminderCalendar(reminders, time.Now())); err != nil {
+				return fmt.Errorf("failed to write calendar: %w", err)
+			}
+			if f, ok := w.(*os.File); ok && f != os.Stdout {
+				if err := f.Close(); err != nil {
+					return fmt.Errorf("failed to write %s: %w", out, err)
+				}
+			}
+
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d reminders to %s\n", len(reminders), dest)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&ics, "ics", true, "Write iCalendar (the only format)")
+	cmd.Flags().StringVar(&out, "out", "", "Write to this file instead of stdout")
+	cmd.Flags().StringVar(&inboxes, "inbox", "", "Inboxes to scan, comma-separated (default: all)")
+
+	return cmd
+}
+
+// reminderImport is the outcome of importing one calendar event.
+type reminderImport struct {
+	Summary  string    `json:"summary"`
+	ChatID   string    `json:"chatID,omitempty"`
+	Title    string    `json:"title,omitempty"`
+	RemindAt time.Time `json:"remindAt,omitzero"`
+	Status   string    `json:"status"` // set, dry-run, skipped or failed
+	Error    string    `json:"error,omitempty"`
+}
+
+func newRemindersImportCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file.ics>",
+		Short: "Set reminders from an iCalendar file",
+		Long: `Set a chat reminder for each event in an iCalendar (.ics) file, at the
+event's start. Use - to read from stdin.
+
+Events written by 'beeper reminders export' name their chat by ID. Other
+events are matched by title: the summary, without a leading "Reply to ",
+must name exactly one chat. Events in the past are skipped, and since a
+chat holds one reminder, only the latest event for each chat is set. An
+event in a time zone that can't be resolved fails on its own:
+  beeper reminders import reminders.ics --dry-run
+  beeper reminders import reminders.ics`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var r io.Reader = os.Stdin
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return fmt.Errorf("failed to open calendar: %w", err)
+				}
+				defer func() { _ = f.Close() }()
+				r = f
+			}
+			cal, err := ical.Parse(r, timeexpr.DefaultHour)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", args[0], err)
+			}
+			if len(cal.Events) == 0 {
+				return fmt.Errorf("no events in %s", args[0])
+			}
+
+			client, err := getClient()
 			if err != nil {
-				return api.UserFriendlyError(err)
+				return err
+			}
+
+			now := time.Now()
+			// A chat holds one reminder, so only its latest event is set.
+			results := make([]reminderImport, 0, len(cal.Events))
+			latest := map[string]int{} // chat ID -> index in results
+			for _, ev := range cal.Events {
+				res := resolveImport(cmd, client, ev, now)
+				if res.Status == "" {
+					if i, ok := latest[res.ChatID]; ok && !res.RemindAt.After(results[i].RemindAt) {
+						res.Status, res.Error = "skipped", supersededImport
+					} else {
+						if ok {
+							results[i].Status, results[i].Error = "skipped", supersededImport
+						}
+						latest[res.ChatID] = len(results)
+					}
+				}
+				results = append(results, res)
 			}
-			defer func() { _ = resp.Body.Close() }()
 
-			if err := api.ParseErrorWithContext(resp, "Chat"); err != nil {
+			failed := 0
+			for i := range results {
+				r := &results[i]
+				if r.Status == "" {
+					r.Status = "dry-run"
+					if !dryRun {
+						r.Status = "set"
+						if err := client.Reminders().Set(cmd.Context(), r.ChatID, r.RemindAt); err != nil {
+							r.Status, r.Error = "failed", err.Error()
+						}
+					}
+				}
+				if r.Status == "failed" {
+					failed++
+				}
+			}
+
+			if err := outfmt.Output(cmd.Context(), results, func(w io.Writer) {
+				tw := outfmt.NewTableWriter(w)
+				tw.SetHeader([]string{"Event", "Chat", "Due", "Status"})
+				for _, r := range results {
+					due, status := "", r.Status
+					if !r.RemindAt.IsZero() {
+						due = formatReminderTime(r.RemindAt)
+					}
+					if r.Error != "" {
+						status += ": " + truncate(r.Error, 50)
+					}
+					tw.Append([]string{truncate(r.Summary, 30), truncate(r.Title, 30), due, status})
+				}
+				tw.Render()
+			}); err != nil {
 				return err
 			}
 
-			fmt.Println("Reminder cleared")
+			if failed > 0 {
+				return fmt.Errorf("%d of %d reminders could not be imported", failed, len(results))
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&chat, "chat", "", "Specify chat by name (searches for matching chat)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Match events to chats without setting reminders")
+
+	return cmd
+}
+
+// supersededImport explains why an earlier event for a chat was not set.
+const supersededImport = "a later event is set for this chat"
+
+// resolveImport finds an event's chat. The result has an empty Status when
+// the reminder can be set, and is skipped or failed otherwise.
+func resolveImport(cmd *cobra.Command, client *api.Client, ev ical.Event, now time.Time) reminderImport {
+	res := reminderImport{Summary: ev.Summary, RemindAt: ev.Start}
+	chatID, title := ical.EventChat(ev)
+	switch {
+	case ev.Err != nil:
+		res.Status, res.Error = "failed", ev.Err.Error()
+		return res
+	case ev.Start.IsZero():
+		res.Status, res.Error = "skipped", "no start time"
+		return res
+	case !ev.Start.After(now):
+		res.Status, res.Error = "skipped", "in the past"
+		return res
+	case chatID == "" && title == "":
+		res.Status, res.Error = "failed", "no chat ID or title"
+		return res
+	}
+
+	if chatID == "" {
+		chat, err := findChatByName(cmd, client, title, true)
+		if err != nil {
+			res.Status, res.Error = "failed", err.Error()
+			return res
+		}
+		chatID, title = chat.ID, chat.Title
+	}
+	res.ChatID, res.Title = chatID, cmp.Or(title, chatID)
+	return res
+}
+
+func newRemindersRecurringCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recurring",
+		Short: "Manage recurring reminders",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List recurring reminders",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openRecurring()
+			if err != nil {
+				return err
+			}
+			items, err := store.Items()
+			if err != nil {
+				return err
+			}
+			if items == nil {
+				items = []schedule.Recurring{}
+			}
+			return outfmt.Output(cmd.Context(), items, func(w io.Writer) {
+				if len(items) == 0 {
+					_, _ = fmt.Fprintln(w, "No recurring reminders")
+					return
+				}
+				tw := outfmt.NewTableWriter(w)
+				tw.SetHeader([]string{"ID", "Every", "Chat", "Next", "Status"})
+				for _, r := range items {
+					next, status := "-", "ok"
+					if !r.Next.IsZero() {
+						next = formatReminderTime(r.Next)
+					}
+					if r.Due(time.Now()) {
+						status = "waiting for run"
+					}
+					if r.LastError != "" {
+						status = "failed: " + truncate(r.LastError, 40)
+					}
+					tw.Append([]string{r.ID, r.Every.String(), truncate(r.ChatTitle, 25), next, status})
+				}
+				tw.Render()
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <id>",
+		Short: "Stop a recurring reminder",
+		Long: `Stop a recurring reminder. The occurrence already set in Beeper stays;
+clear it with 'beeper reminders clear'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openRecurring()
+			if err != nil {
+				return err
+			}
+			r, err := store.Remove(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Stopped recurring reminder %s for %s\n", r.ID, r.ChatTitle)
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+func newRemindersRunCmd() *cobra.Command {
+	var (
+		once     bool
+		interval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Re-arm recurring reminders as they pass",
+		Long: `Set the next occurrence of every recurring reminder whose last one has
+passed.
+
+Without --once, keeps running and checks every --interval until
+interrupted. With --once, re-arms what is due and exits, for cron or a
+systemd timer:
+  */5 * * * * beeper reminders run --once`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openRecurring()
+			if err != nil {
+				return err
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+
+			return runPasses(cmd, passRunner{
+				once:     once,
+				interval: interval,
+				banner:   fmt.Sprintf("Re-arming recurring reminders from %s", store.Path()),
+				failed:   "%d recurring reminders could not be set; they will be retried",
+				pass: func(ctx context.Context, stderr io.Writer) (int, error) {
+					results, err := schedule.Rearm(ctx, store, client.Reminders(), time.Now())
+					failed := 0
+					for _, r := range results {
+						if err := outfmt.Output(ctx, r.Reminder, func(io.Writer) {
+							if r.Err != nil {
+								_, _ = fmt.Fprintf(stderr, "✗ %s (%s): %v\n", r.Reminder.ChatTitle, r.Reminder.Every, r.Err)
+							} else {
+								_, _ = fmt.Fprintf(stderr, "✓ %s: next reminder %s\n", r.Reminder.ChatTitle, formatReminderTime(r.Reminder.Next))
+							}
+						}); err != nil {
+							return failed, err
+						}
+						if r.Err != nil {
+							failed++
+						}
+					}
+					return failed, err
+				},
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&once, "once", false, "Re-arm what is due and exit")
+	cmd.Flags().DurationVar(&interval, "interval", time.Minute, "How often to check")
 
 	return cmd
 }
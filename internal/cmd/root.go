@@ -14,6 +14,11 @@ type rootFlags struct {
 	Query   string
 	Color   string
 	Debug   bool
+	APIURL  string
+	Profile string
+
+	KeyringBackend string
+	TokenStdin     bool
 }
 
 var flags rootFlags
@@ -39,6 +44,10 @@ func NewRootCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&flags.Query, "query", "", "JQ filter for JSON output")
 	cmd.PersistentFlags().StringVar(&flags.Color, "color", "auto", "Color mode: auto|always|never")
 	cmd.PersistentFlags().BoolVar(&flags.Debug, "debug", false, "Enable debug logging for API requests")
+	cmd.PersistentFlags().StringVar(&flags.Profile, "profile", "", "Auth profile to use (env: BEEPER_PROFILE)")
+	cmd.PersistentFlags().StringVar(&flags.KeyringBackend, "keyring-backend", "", "Credential backend: auto|keychain|secret-service|kwallet|pass|file|wincred")
+	cmd.PersistentFlags().BoolVar(&flags.TokenStdin, "token-stdin", false, "Read the API token from stdin instead of the keyring")
+	cmd.PersistentFlags().StringVar(&flags.APIURL, "api-url", "", "Beeper API address: http(s)://host:port or unix:///path (env: BEEPER_API_URL)")
 
 	cmd.AddCommand(newAuthCmd())
 	cmd.AddCommand(newAccountsCmd())
@@ -46,6 +55,12 @@ func NewRootCmd() *cobra.Command {
 	cmd.AddCommand(newMessagesCmd())
 	cmd.AddCommand(newRemindersCmd())
 	cmd.AddCommand(newFocusCmd())
+	cmd.AddCommand(newSyncCmd())
+	cmd.AddCommand(newSearchCmd())
+	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newWatchCmd())
+	cmd.AddCommand(newScheduleCmd())
+	cmd.AddCommand(newTemplatesCmd())
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newCompletionCmd())
 
@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+	"github.com/salmonumbrella/beeper-cli/internal/schedule"
+)
+
+func newScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Send messages later",
+		Long: `Queue messages to send at a later time.
+
+Scheduled messages are kept in a queue file in the data directory and
+delivered by 'beeper schedule run', either as a long-running process or
+from cron:
+  beeper schedule send --to "Kishan" --at 09:00 --text "Morning!"
+  beeper schedule run                    # keep running, send as messages fall due
+  * * * * * beeper schedule run --once   # or from cron, once a minute
+
+Beeper Desktop must be running when a message falls due. Failed sends stay
+in the queue and are retried with backoff.`,
+	}
+
+	cmd.AddCommand(newScheduleSendCmd())
+	cmd.AddCommand(newScheduleListCmd())
+	cmd.AddCommand(newScheduleCancelCmd())
+	cmd.AddCommand(newScheduleRetryCmd())
+	cmd.AddCommand(newScheduleRunCmd())
+
+	return cmd
+}
+
+// openSchedule opens the queue in the data directory.
+func openSchedule() (*schedule.Queue, error) {
+	path, err := schedule.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return schedule.Open(path), nil
+}
+
+func newScheduleSendCmd() *cobra.Command {
+	var (
+		at       string
+		tz       string
+		to       string
+		text     string
+		textFile string
+		replyTo  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "send [chat-id]",
+		Short: "Schedule a message",
+		Long: `Schedule a message to be sent later.
+
+--at accepts:
+  +90m, in 2h           a delay from now
+  17:30, 5pm, 9:15am    the next time the clock shows this
+  tomorrow 9am, fri 2pm a day and a time (days alone mean 9:00)
+  next fri, eod, eow    next Friday, 17:00 today, 17:00 Friday
+  2025-01-02 09:30      a date and time
+  RFC 3339              e.g. 2025-01-02T09:30:00+09:00
+
+Times are read in your local time zone, or in --tz (an IANA name such as
+Asia/Tokyo) to send at a time of day somewhere else:
+  beeper schedule send --to "Tokyo team" --at 9am --tz Asia/Tokyo --text "Good morning"`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sendAt, err := parseTimeFlag("at", at, tz)
+			if err != nil {
+				return err
+			}
+			if !sendAt.After(time.Now()) {
+				return fmt.Errorf("--at %s is in the past", sendAt.Format(absoluteLayout))
+			}
+
+			if text, err = readMessageText(text, textFile); err != nil {
+				return err
+			}
+			if text == "" {
+				return fmt.Errorf("--text or --text-file is required")
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+
+			var chatID string
+			if to != "" {
+				chatID, err = resolveChatByName(cmd, client, to)
+				if err != nil {
+					return err
+				}
+			} else if len(args) == 1 {
+				chatID = args[0]
+			} else {
+				return fmt.Errorf("either <chat-id> argument or --to flag is required")
+			}
+
+			// Check the chat exists now rather than when the message falls due.
+			chat, err := client.Chats().Get(cmd.Context(), chatID)
+			if err != nil {
+				return err
+			}
+
+			q, err := openSchedule()
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Scheduling message for %s\n", formatAbsolute(sendAt))
+			item, err := q.Add(schedule.Item{
+				ChatID:    chatID,
+				ChatTitle: chat.Title,
+				Text:      text,
+				ReplyTo:   replyTo,
+				SendAt:    sendAt,
+			})
+			if err != nil {
+				return err
+			}
+
+			return outfmt.Output(cmd.Context(), item, func(w io.Writer) {
+				_, _ = fmt.Fprintf(w, "Scheduled %s for %s to %s\n", item.ID, formatAbsolute(item.SendAt), item.ChatTitle)
+				_, _ = fmt.Fprintln(w, "Messages are sent by 'beeper schedule run'.")
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&at, "at", "", "When to send (e.g. in 2h, 17:30, \"tomorrow 9am\", \"next fri\", 2025-01-02 09:30)")
+	cmd.Flags().StringVar(&tz, "tz", "", "Time zone for --at, e.g. Europe/Berlin (default: local)")
+	cmd.Flags().StringVar(&to, "to", "", "Send to chat by name (searches for matching chat)")
+	cmd.Flags().StringVar(&text, "text", "", "Message text ('-' reads stdin)")
+	cmd.Flags().StringVar(&textFile, "text-file", "", "Read the message text from a file")
+	cmd.Flags().StringVar(&replyTo, "reply-to", "", "Reply to message ID")
+	_ = cmd.MarkFlagRequired("at")
+	cmd.MarkFlagsMutuallyExclusive("text", "text-file")
+
+	return cmd
+}
+
+func newScheduleListCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List scheduled messages",
+		Long: `List queued and failed messages. Use --all to include sent ones, which
+are kept for a week with the ID of the delivered message.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			q, err := openSchedule()
+			if err != nil {
+				return err
+			}
+			items, err := q.Items()
+			if err != nil {
+				return err
+			}
+			if !all {
+				pending := make([]schedule.Item, 0, len(items))
+				for _, it := range items {
+					if it.Status != schedule.StatusSent {
+						pending = append(pending, it)
+					}
+				}
+				items = pending
+			}
+
+			colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(cmd.Context()))
+			return outfmt.Output(cmd.Context(), items, func(w io.Writer) {
+				if len(items) == 0 {
+					_, _ = fmt.Fprintln(w, "No scheduled messages")
+					return
+				}
+				tw := outfmt.NewTableWriter(w)
+				headers := []string{"ID", "Send At", "Chat", "Status", "Text"}
+				if colorEnabled {
+					for i, h := range headers {
+						headers[i] = outfmt.Colorize(h, outfmt.Bold, true)
+					}
+				}
+				tw.SetHeader(headers)
+				for _, it := range items {
+					tw.Append([]string{
+						it.ID,
+						formatSendAt(it.SendAt),
+						truncate(it.ChatTitle, 25),
+						scheduleStatus(it),
+						truncate(strings.ReplaceAll(it.Text, "\n", " "), 40),
+					})
+				}
+				tw.Render()
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Include messages already sent")
+
+	return cmd
+}
+
+// scheduleStatus describes an item's state for the list table.
+func scheduleStatus(it schedule.Item) string {
+	switch it.Status {
+	case schedule.StatusSent:
+		return "sent " + it.MessageID
+	case schedule.StatusFailed:
+		if it.Attempts >= schedule.MaxAttempts {
+			return fmt.Sprintf("failed (gave up): %s", truncate(it.LastError, 40))
+		}
+		return fmt.Sprintf("failed (attempt %d): %s", it.Attempts, truncate(it.LastError, 40))
+	case schedule.StatusInterrupted:
+		return "interrupted: check the chat, then 'schedule retry' to send again"
+	}
+	return it.Status
+}
+
+func newScheduleCancelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel <id>",
+		Short: "Cancel a scheduled message",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			q, err := openSchedule()
+			if err != nil {
+				return err
+			}
+			item, err := q.Cancel(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Cancelled %s to %s\n", item.ID, item.ChatTitle)
+			return nil
+		},
+	}
+}
+
+func newScheduleRetryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry <id>",
+		Short: "Retry a failed or interrupted message on the next run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			q, err := openSchedule()
+			if err != nil {
+				return err
+			}
+			if err := q.Retry(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Message %s will be retried on the next run\n", args[0])
+			return nil
+		},
+	}
+}
+
+// sentRetention is how long delivered items stay listed.
+const sentRetention = 7 * 24 * time.Hour
+
+func newScheduleRunCmd() *cobra.Command {
+	var (
+		once     bool
+		interval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Send scheduled messages as they fall due",
+		Long: `Deliver scheduled messages that are due.
+
+Without --once, keeps running and checks the queue every --interval until
+interrupted. With --once, sends whatever is due and exits, for cron or a
+systemd timer. Several runners may share a queue; each message is sent once.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			q, err := openSchedule()
+			if err != nil {
+				return err
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+
+			return runPasses(cmd, passRunner{
+				once:     once,
+				interval: interval,
+				banner:   fmt.Sprintf("Sending scheduled messages from %s", q.Path()),
+				failed:   "%d scheduled messages failed; they will be retried",
+				pass: func(ctx context.Context, stderr io.Writer) (int, error) {
+					results, err := schedule.Dispatch(ctx, q, client.Messages(), time.Now())
+					failed := 0
+					for _, r := range results {
+						if err := outfmt.Output(ctx, r.Item, func(io.Writer) {
+							if r.Err != nil {
+								_, _ = fmt.Fprintf(stderr, "✗ %s to %s: %v\n", r.Item.ID, r.Item.ChatTitle, r.Err)
+							} else {
+								_, _ = fmt.Fprintf(stderr, "✓ %s to %s (message %s)\n", r.Item.ID, r.Item.ChatTitle, r.Item.MessageID)
+							}
+						}); err != nil {
+							return failed, err
+						}
+						if r.Err != nil {
+							failed++
+						}
+					}
+					_, _ = q.Prune(time.Now().Add(-sentRetention))
+					return failed, err
+				},
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&once, "once", false, "Send what is due and exit")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "How often to check the queue")
+
+	return cmd
+}
+
+// passRunner describes a background command like 'schedule run': a pass
+// over due work that repeats every interval, or runs once.
+type passRunner struct {
+	once     bool
+	interval time.Duration
+	// banner is printed before running continuously.
+	banner string
+	// failed formats the --once error from the number of failed items.
+	failed string
+	// pass handles what is due, printing a line per item to stderr, and
+	// returns how many items failed and any error that stopped the pass.
+	pass func(ctx context.Context, stderr io.Writer) (failed int, err error)
+}
+
+// runPasses runs r until interrupted, or once. Without --once an error that
+// stops a pass is printed as a warning and the next pass tries again.
+func runPasses(cmd *cobra.Command, r passRunner) error {
+	if r.interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	stderr := cmd.ErrOrStderr()
+	if !r.once {
+		_, _ = fmt.Fprintf(stderr, "%s. Press Ctrl-C to stop.\n", r.banner)
+	}
+
+	for {
+		failed, err := r.pass(ctx, stderr)
+		if r.once {
+			if err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf(r.failed, failed)
+			}
+			return nil
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "warning: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return ctx.Err()
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// formatSendAt shows a send time with its date, since it is usually not today.
+func formatSendAt(t time.Time) string {
+	return t.Local().Format("Mon Jan 2 15:04")
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/index"
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+)
+
+func newSearchCmd() *cobra.Command {
+	var (
+		offline  bool
+		chatIDs  string
+		after    string
+		before   string
+		sender   string
+		networks string
+		limit    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search messages",
+		Long: `Search messages, online through Beeper Desktop or offline in the local index.
+
+With --offline the query runs against the index built by 'beeper sync' and
+works while Beeper Desktop is closed. Results are ranked by relevance.
+
+Dates accept absolute and relative forms: 2024-01-31, 3d, yesterday,
+"last monday" or 2024-Q3. Online, --before and --sender are applied locally
+while paging through results.
+
+Query syntax (offline):
+  dinner sunday          both words
+  "see you soon"         exact phrase
+  dinner OR lunch        either word
+  dinner -pizza          exclude a word (also: dinner NOT pizza)
+  (dinner OR lunch) fri  group with parentheses
+  deploy*                prefix match
+
+Examples:
+  beeper search --offline "quarterly report" --after 2024-01-01
+  beeper search --offline invoice --sender alice --network whatsapp
+  beeper search --offline '"flight number"' -o json
+  beeper search invoice --after 2024-Q3 --before 2024-Q4 --sender alice`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			afterTime, err := parseDateFlag("after", after)
+			if err != nil {
+				return err
+			}
+			beforeTime, err := parseDateFlag("before", before)
+			if err != nil {
+				return err
+			}
+
+			if !offline {
+				filter := api.MessageFilter{Before: beforeTime, Sender: sender}
+				return searchOnline(cmd, args[0], chatIDs, splitList(networks), afterTime, filter, limit)
+			}
+
+			ix, err := openIndex()
+			if err != nil {
+				return err
+			}
+			defer func() { _ = ix.Close() }()
+
+			results, err := ix.Search(cmd.Context(), index.Query{
+				Text:     args[0],
+				After:    afterTime,
+				Before:   beforeTime,
+				Sender:   sender,
+				Networks: splitList(networks),
+				ChatIDs:  splitList(chatIDs),
+				Limit:    limit,
+			})
+			if err != nil {
+				return err
+			}
+			if len(results) == 0 {
+				if st, err := ix.Stats(cmd.Context()); err == nil && st.Messages == 0 {
+					return fmt.Errorf("the local index is empty. Run: beeper sync")
+				}
+			}
+
+			colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(cmd.Context()))
+			if outfmt.GetFormat(cmd.Context()) == "json" {
+				for i := range results {
+					results[i].Snippet = highlight(results[i].Snippet, false)
+				}
+			}
+			return outfmt.Output(cmd.Context(), results, func(w io.Writer) {
+				tw := outfmt.NewTableWriter(w)
+				tw.SetHeader([]string{"Chat", "Sender", "Time", "Message"})
+				for _, r := range results {
+					tw.Append([]string{
+						truncate(r.ChatTitle, 20),
+						truncate(r.SenderName, 15),
+						formatTime(r.Timestamp),
+						highlight(r.Snippet, colorEnabled),
+					})
+				}
+				tw.Render()
+				_, _ = fmt.Fprintf(w, "\n%d results\n", len(results))
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&offline, "offline", false, "Search the local index instead of Beeper Desktop")
+	cmd.Flags().StringVar(&chatIDs, "chat", "", "Filter by chat ID(s), comma-separated")
+	cmd.Flags().StringVar(&after, "after", "", "Messages on or after date (2024-01-31, 3d, \"last monday\", 2024-Q3...)")
+	cmd.Flags().StringVar(&before, "before", "", "Messages before date (same forms as --after)")
+	cmd.Flags().StringVar(&sender, "sender", "", "Filter by sender name")
+	cmd.Flags().StringVar(&networks, "network", "", "Filter by network or account ID(s), comma-separated")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of results (0 = no limit)")
+
+	return cmd
+}
+
+// searchOnline runs the query through Beeper Desktop's search API, applying
+// the criteria it doesn't support on the client.
+func searchOnline(cmd *cobra.Command, query, chatIDs string, networks []string, after time.Time, filter api.MessageFilter, limit int) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	params := api.SearchMessagesParams{
+		Query:      query,
+		AccountIDs: accountIDs(),
+		ChatIDs:    splitList(chatIDs),
+	}
+	if !after.IsZero() {
+		params.DateAfter = after.Format(time.RFC3339)
+	}
+	if len(networks) > 0 {
+		if params.AccountIDs, err = networkAccountIDs(cmd.Context(), client, networks); err != nil {
+			return err
+		}
+	}
+	if len(filter.Local()) == 0 {
+		return streamSearchHits(cmd, client, client.Messages().SearchAll(cmd.Context(), params, limit))
+	}
+	return searchFiltered(cmd, client, params, filter, searchLimits{all: true, max: limit, maxScan: defaultSearchScan})
+}
+
+// highlight renders the index's match markers as bold text, or drops them.
+func highlight(snippet string, color bool) string {
+	snippet = strings.ReplaceAll(snippet, "\n", " ")
+	if !color {
+		return strings.NewReplacer(index.MatchStart, "", index.MatchEnd, "").Replace(snippet)
+	}
+	return strings.NewReplacer(index.MatchStart, outfmt.Bold, index.MatchEnd, outfmt.Reset).Replace(snippet)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/index"
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+)
+
+func newSyncCmd() *cobra.Command {
+	var (
+		chatIDs  string
+		maxPages int
+		status   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Copy messages into the local search index",
+		Long: `Copy chats and messages into a local index for offline search.
+
+The first run pages through the full history of every chat, which can take a
+while. Later runs only fetch chats with new activity, and stop as soon as they
+reach messages already stored. An interrupted sync picks up where it left off.
+
+  beeper sync                      # everything
+  beeper sync --max-pages 20       # at most 20 pages per chat this run
+  beeper sync --chat '!abc:beeper.com'
+  beeper sync --status             # show what is indexed
+
+The index lives in the data directory and is searched with:
+  beeper search --offline <query>`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ix, err := openIndex()
+			if err != nil {
+				return err
+			}
+			defer func() { _ = ix.Close() }()
+
+			if status {
+				return printIndexStats(cmd, ix)
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+
+			stderr := cmd.ErrOrStderr()
+			report, err := index.Sync(cmd.Context(), client, ix, index.SyncOptions{
+				ChatIDs:    splitList(chatIDs),
+				AccountIDs: accountIDs(),
+				MaxPages:   maxPages,
+			}, func(cs index.ChatSync) {
+				switch {
+				case cs.Error != "":
+					_, _ = fmt.Fprintf(stderr, "  ✗ %s: %s\n", cs.Title, cs.Error)
+				case cs.Skipped:
+				case cs.Complete:
+					_, _ = fmt.Fprintf(stderr, "  ✓ %s (+%d)\n", cs.Title, cs.Added)
+				default:
+					_, _ = fmt.Fprintf(stderr, "  … %s (+%d, more history remaining)\n", cs.Title, cs.Added)
+				}
+			})
+			if err != nil {
+				return err
+			}
+			if report.Scan != nil {
+				_, _ = fmt.Fprintln(stderr, report.Scan.Summary())
+			}
+
+			var failed, partial int
+			for _, cs := range report.Chats {
+				if cs.Error != "" {
+					failed++
+				} else if !cs.Complete {
+					partial++
+				}
+			}
+
+			return outfmt.Output(cmd.Context(), report, func(w io.Writer) {
+				_, _ = fmt.Fprintf(w, "Synced %d chats, %d new messages\n", len(report.Chats), report.Added)
+				if failed > 0 {
+					_, _ = fmt.Fprintf(w, "%d chats failed; run sync again to retry\n", failed)
+				}
+				if partial > 0 {
+					_, _ = fmt.Fprintf(w, "%d chats have older history left; run sync again to continue\n", partial)
+				}
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&chatIDs, "chat", "", "Only sync these chat ID(s), comma-separated")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Fetch at most this many pages per chat (0 = no limit)")
+	cmd.Flags().BoolVar(&status, "status", false, "Show index statistics without syncing")
+
+	return cmd
+}
+
+func openIndex() (*index.Index, error) {
+	path, err := index.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return index.Open(path)
+}
+
+func printIndexStats(cmd *cobra.Command, ix *index.Index) error {
+	st, err := ix.Stats(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to read index: %w", err)
+	}
+	return outfmt.Output(cmd.Context(), st, func(w io.Writer) {
+		_, _ = fmt.Fprintf(w, "Chats:     %d (%d with full history)\n", st.Chats, st.Complete)
+		_, _ = fmt.Fprintf(w, "Messages:  %d\n", st.Messages)
+		if st.Messages > 0 {
+			_, _ = fmt.Fprintf(w, "Oldest:    %s\n", st.Oldest.Local().Format("Jan 2, 2006"))
+			_, _ = fmt.Fprintf(w, "Newest:    %s\n", st.Newest.Local().Format("Jan 2, 2006"))
+		}
+	})
+}
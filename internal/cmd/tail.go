@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+)
+
+func newMessagesTailCmd() *cobra.Command {
+	var (
+		follow      bool
+		lines       int
+		interval    time.Duration
+		maxInterval time.Duration
+		noPush      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tail [chat...]",
+		Short: "Show the latest messages and follow new ones",
+		Long: `Show the latest messages in one or more chats, and with -f keep printing
+new messages as they arrive. With no chats and -f, follows every chat.
+
+Chats can be given by ID or by name. New messages come from Beeper Desktop's
+event stream when it offers one; otherwise the chats are polled, quickly
+while messages are arriving and backing off to --max-interval when idle.
+
+With -o json each message is printed as one JSON object per line, with the
+chat included, which suits piping into scripts:
+  beeper messages tail -f -o json | jq -r 'select(.text | test("urgent"; "i")) | .chat.title'
+
+Examples:
+  beeper messages tail "Kishan"            # last 10 messages
+  beeper messages tail -f "Kishan" "Team"  # follow two chats
+  beeper messages tail -f                  # follow everything
+
+Press Ctrl-C to stop.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !follow {
+				return fmt.Errorf("give at least one chat, or use -f to follow all chats")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+
+			var chatIDs []string
+			for _, ref := range args {
+				id, err := resolveChatRef(cmd, client, ref)
+				if err != nil {
+					return err
+				}
+				if !slices.Contains(chatIDs, id) {
+					chatIDs = append(chatIDs, id)
+				}
+			}
+
+			senders := api.NewSenders(client.Chats())
+			for _, id := range chatIDs {
+				if lines <= 0 {
+					break
+				}
+				if err := printRecentMessages(ctx, client, senders, id, lines); err != nil {
+					return err
+				}
+			}
+			if !follow {
+				return nil
+			}
+
+			stderr := cmd.ErrOrStderr()
+			for ev, err := range client.Messages().Follow(ctx, api.FollowParams{
+				ChatIDs:     chatIDs,
+				AccountIDs:  accountIDs(),
+				MinInterval: interval,
+				MaxInterval: maxInterval,
+				NoPush:      noPush,
+			}) {
+				if ctx.Err() != nil {
+					break
+				}
+				if err != nil {
+					_, _ = fmt.Fprintf(stderr, "warning: %v\n", err)
+					continue
+				}
+				if err := printMessageEvent(ctx, senders, ev); err != nil {
+					return err
+				}
+			}
+
+			if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new messages as they arrive")
+	cmd.Flags().IntVarP(&lines, "lines", "n", 10, "Number of recent messages to show per chat first")
+	cmd.Flags().DurationVar(&interval, "interval", api.DefaultFollowMinInterval, "Fastest polling interval")
+	cmd.Flags().DurationVar(&maxInterval, "max-interval", api.DefaultFollowMaxInterval, "Slowest polling interval when idle")
+	cmd.Flags().BoolVar(&noPush, "no-push", false, "Always poll, even if Beeper Desktop offers an event stream")
+
+	return cmd
+}
+
+// printRecentMessages prints the last n messages of a chat, oldest first.
+func printRecentMessages(ctx context.Context, client *api.Client, senders *api.Senders, chatID string, n int) error {
+	chat, err := client.Chats().Get(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	senders.Add(chat)
+
+	var messages []api.Message
+	for m, err := range client.Messages().All(ctx, chatID, api.ListMessagesParams{}, n) {
+		if err != nil {
+			return err
+		}
+		messages = append(messages, m)
+	}
+	slices.SortStableFunc(messages, func(a, b api.Message) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	for _, m := range messages {
+		if err := printMessageEvent(ctx, senders, api.MessageEvent{Message: m, Chat: chat}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printMessageEvent prints one message as a text line or a JSON object.
+func printMessageEvent(ctx context.Context, senders *api.Senders, ev api.MessageEvent) error {
+	return outfmt.Output(ctx, ev, func(w io.Writer) {
+		chatName := ev.ChatID
+		if ev.Chat != nil && ev.Chat.Title != "" {
+			chatName = ev.Chat.Title
+		}
+		_, _ = fmt.Fprintf(w, "[%s] %s · %s: %s\n", formatTime(ev.Timestamp), chatName, senders.Name(ctx, ev.Message, ev.Chat), ev.Text)
+	})
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/compose"
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+	"github.com/salmonumbrella/beeper-cli/internal/templates"
+)
+
+func newTemplatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Manage message templates",
+		Long: `Manage reusable message templates.
+
+Templates are Go text/template files (NAME.tmpl) in the templates directory
+under your config dir. Use them with:
+  beeper messages send --to "Team" --template standup --var today="Ship it"
+  beeper messages broadcast --to-file chats.txt --template release --var version=1.4
+  beeper focus --chat "Team" --template standup
+
+Fields:
+  {{.Title}} {{.Network}} {{.Type}} {{.ID}}   the chat
+  {{.FirstNames}}                             "Ann, Bob and Cy" (or the title)
+  {{range .Participants}}{{.FirstName}} {{.FullName}} {{.Username}}{{end}}
+  {{.Vars.key}}                               a --var key=value (required)
+  {{index .Vars "key"}}                       an optional --var
+  {{.Now}}                                    the current time
+
+Helpers:
+  date "Mon Jan 2" .Now     format a time (Go layout)
+  addDays 7 .Now            shift a time by days
+  weekday .Now              "Monday"
+  default "n/a" VALUE       VALUE, or "n/a" when empty
+  upper, lower, join
+
+A leading {{/* comment */}} is shown by 'beeper templates list'.`,
+	}
+
+	cmd.AddCommand(newTemplatesListCmd())
+	cmd.AddCommand(newTemplatesShowCmd())
+	cmd.AddCommand(newTemplatesNewCmd())
+
+	return cmd
+}
+
+func newTemplatesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List templates",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := templates.Dir()
+			if err != nil {
+				return err
+			}
+			infos, err := templates.List(dir)
+			if err != nil {
+				return err
+			}
+
+			return outfmt.Output(cmd.Context(), infos, func(w io.Writer) {
+				if len(infos) == 0 {
+					_, _ = fmt.Fprintf(w, "No templates in %s. Create one with: beeper templates new <name>\n", dir)
+					return
+				}
+				tw := outfmt.NewTableWriter(w)
+				tw.SetHeader([]string{"Name", "Description"})
+				for _, t := range infos {
+					tw.Append([]string{t.Name, truncate(t.Summary, 60)})
+				}
+				tw.Render()
+			})
+		},
+	}
+}
+
+func newTemplatesShowCmd() *cobra.Command {
+	var (
+		chat string
+		vars []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a template, or render it for a chat",
+		Long: `Print a template's source. With --chat, render it for that chat instead,
+to preview what would be sent:
+  beeper templates show standup --chat "Team" --var today="Ship it"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := templates.Dir()
+			if err != nil {
+				return err
+			}
+
+			if chat == "" && len(vars) == 0 {
+				text, err := templates.Read(dir, args[0])
+				if err != nil {
+					return err
+				}
+				// Check it parses, so mistakes show up before a send.
+				if _, err := templates.Parse(args[0], text); err != nil {
+					return err
+				}
+				fmt.Print(text)
+				return nil
+			}
+
+			var client *api.Client
+			chatID := ""
+			if chat != "" {
+				client, err = getClient()
+				if err != nil {
+					return err
+				}
+				chatID, err = resolveChatRef(cmd, client, chat)
+				if err != nil {
+					return err
+				}
+			}
+			text, err := renderTemplate(cmd, client, args[0], chatID, vars)
+			if err != nil {
+				return err
+			}
+			fmt.Println(text)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&chat, "chat", "", "Render for this chat (ID or name)")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable key=value (repeatable)")
+
+	return cmd
+}
+
+func newTemplatesNewCmd() *cobra.Command {
+	var text string
+
+	cmd := &cobra.Command{
+		Use:   "new <name>",
+		Short: "Create a template",
+		Long: `Create a template and open it in $VISUAL or $EDITOR, starting from an
+example. Pass --text to write the template directly instead:
+  beeper templates new standup
+  beeper templates new thanks --text 'Thanks {{.FirstNames}}!'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if text != "" {
+				if _, err := templates.Parse(args[0], text); err != nil {
+					return err
+				}
+			}
+
+			dir, err := templates.Dir()
+			if err != nil {
+				return err
+			}
+			path, err := templates.Create(dir, args[0], text)
+			if err != nil {
+				return err
+			}
+
+			if text == "" {
+				if err := compose.EditFile(cmd.Context(), compose.Editor(), path); err != nil {
+					return err
+				}
+				if _, err := templates.Load(dir, args[0]); err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\nFix it with: %s %s\n", err, compose.Editor(), path)
+				}
+			}
+			fmt.Printf("Created template %s (%s)\n", args[0], path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&text, "text", "", "Template text (skips the editor)")
+
+	return cmd
+}
+
+// renderTemplate renders template name for chatID with --var pairs. With no
+// chat (or no client) only Vars and the date helpers have values.
+func renderTemplate(cmd *cobra.Command, client *api.Client, name, chatID string, vars []string) (string, error) {
+	values, err := templates.ParseVars(vars)
+	if err != nil {
+		return "", err
+	}
+	dir, err := templates.Dir()
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := templates.Load(dir, name)
+	if err != nil {
+		return "", err
+	}
+
+	var chat *api.Chat
+	if client != nil && chatID != "" {
+		chat, err = client.Chats().Get(cmd.Context(), chatID)
+		if err != nil {
+			return "", err
+		}
+	}
+	return tmpl.Render(templates.NewData(chat, values, time.Now()))
+}
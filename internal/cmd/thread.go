@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+	"github.com/salmonumbrella/beeper-cli/internal/thread"
+)
+
+// defaultThreadScan bounds how much history `messages thread` reads.
+const defaultThreadScan = 2000
+
+func newMessagesThreadCmd() *cobra.Command {
+	var (
+		chat    string
+		maxScan int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "thread <message-id>",
+		Short: "Show the reply chain around a message",
+		Long: `Show the whole reply thread a message belongs to: the message it answers,
+that message's parent and so on up to the first message, plus every reply
+below it. The chosen message is marked with ▶.
+
+  beeper messages thread <message-id> --chat "Team"
+
+History is read newest first until the thread's first message is found, up
+to --max-scan messages. With -o json the thread is a nested object of
+{"message": ..., "replies": [...]}.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			messageID := args[0]
+			if chat == "" {
+				return fmt.Errorf("--chat is required")
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			chatID, err := resolveChatRef(cmd, client, chat)
+			if err != nil {
+				return err
+			}
+			chatInfo, err := client.Chats().Get(cmd.Context(), chatID)
+			if err != nil {
+				return err
+			}
+
+			msgs, err := scanForThread(cmd, client, chatID, messageID, maxScan)
+			if err != nil {
+				return err
+			}
+			root := thread.Chain(msgs, messageID)
+			if root == nil {
+				return fmt.Errorf("message %s not found in the last %d messages of %s (raise --max-scan)", messageID, len(msgs), chatInfo.Title)
+			}
+
+			colorEnabled := outfmt.ShouldColorize(outfmt.GetColor(cmd.Context()))
+			return outfmt.Output(cmd.Context(), root, func(w io.Writer) {
+				thread.RenderTree(w, []*thread.Node{root}, thread.Options{
+					Sender:    func(m api.Message) string { return api.SenderName(m, chatInfo) },
+					Color:     colorEnabled,
+					Highlight: messageID,
+				})
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&chat, "chat", "", "Chat the message is in (ID or name)")
+	cmd.Flags().IntVar(&maxScan, "max-scan", defaultThreadScan, "Most messages to read while looking for the thread")
+
+	return cmd
+}
+
+// scanForThread reads a chat's history, newest first, until it holds the
+// message and every ancestor it replies to. Replies are newer than what they
+// answer, so by then every reply below the thread's root has been read too.
+func scanForThread(cmd *cobra.Command, client *api.Client, chatID, messageID string, maxScan int) ([]api.Message, error) {
+	var msgs []api.Message
+	byID := make(map[string]api.Message)
+	for m, err := range client.Messages().All(cmd.Context(), chatID, api.ListMessagesParams{}, maxScan) {
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+		byID[m.ID] = m
+		if threadComplete(byID, messageID) {
+			break
+		}
+	}
+	return msgs, nil
+}
+
+// threadComplete reports whether messageID and its whole ancestor chain
+// have been read.
+func threadComplete(byID map[string]api.Message, messageID string) bool {
+	m, ok := byID[messageID]
+	for steps := 0; ok && steps <= len(byID); steps++ {
+		if m.ReplyTo == nil || m.ReplyTo.ID == "" {
+			return true
+		}
+		m, ok = byID[m.ReplyTo.ID]
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/timeexpr"
+)
+
+// parseDateFlag parses a date flag in local time, accepting absolute dates,
+// relative forms like "3d", "last monday" and "2024-Q3", and future times
+// like "next fri" or "in 2d". An empty value gives the zero time.
+func parseDateFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	now := time.Now()
+	t, err := timeexpr.ParseDate(value, now)
+	if err != nil {
+		if t, err := timeexpr.ParseTime(value, now, time.Local); err == nil {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("invalid --%s date %q. Use: %s", name, value, timeexpr.Formats)
+	}
+	return t, nil
+}
+
+// parseTimeFlag parses a future time flag such as "tomorrow 9am" in the
+// --tz zone (local when empty).
+func parseTimeFlag(name, value, tz string) (time.Time, error) {
+	loc := time.Local
+	if tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --tz: %w", err)
+		}
+		loc = l
+	}
+	t, err := timeexpr.ParseTime(value, time.Now(), loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s: %w. Use: %s", name, err, timeexpr.TimeFormats)
+	}
+	return t, nil
+}
+
+// absoluteLayout shows a time in full, with its zone.
+const absoluteLayout = "Mon Jan 2, 2006 3:04 PM MST"
+
+// formatAbsolute shows t in full with its zone, plus how far away it is, so
+// users can check how a time expression was read.
+func formatAbsolute(t time.Time) string {
+	d := time.Until(t).Round(time.Minute)
+	rel := "now"
+	switch {
+	case d >= 24*time.Hour:
+		rel = fmt.Sprintf("in %dd %dh", d/(24*time.Hour), d%(24*time.Hour)/time.Hour)
+	case d >= time.Hour:
+		rel = fmt.Sprintf("in %dh %dm", d/time.Hour, d%time.Hour/time.Minute)
+	case d > 0:
+		rel = fmt.Sprintf("in %dm", d/time.Minute)
+	case d < 0:
+		rel = "in the past"
+	}
+	return fmt.Sprintf("%s (%s)", t.Format(absoluteLayout), rel)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/watch"
+)
+
+func newWatchCmd() *cobra.Command {
+	var (
+		match      string
+		chats      []string
+		networks   string
+		execCmd    string
+		webhook    string
+		name       string
+		includeOwn bool
+		catchUp    time.Duration
+		noPush     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Run a command or webhook for matching messages",
+		Long: `Watch incoming messages and run a hook for each one that matches.
+
+Filters (all optional, combined with AND):
+  --match     Go regular expression tested against the message text
+  --chat      chat ID or name; repeat for several chats (default: all chats)
+  --network   network names or account IDs, comma-separated
+
+Hooks (at least one):
+  --exec      shell command; gets the message as JSON on stdin, plus
+              BEEPER_MESSAGE_ID, BEEPER_CHAT_ID, BEEPER_CHAT_TITLE,
+              BEEPER_SENDER, BEEPER_SENDER_ID and BEEPER_TEXT
+  --webhook   URL to POST the message JSON to
+
+The payload is the message object with added "chatTitle" and "senderName"
+fields; BEEPER_SENDER is the sender's display name.
+
+Each message fires at most once: handled message IDs are kept in the data
+directory, so a restarted watcher neither repeats hooks nor (within
+--catch-up) misses messages that arrived while it was stopped. Give
+watchers distinct --name values to keep their state separate.
+
+Examples:
+  beeper watch --match '(?i)\b(sev[12]|outage)\b' --network slack --exec 'notify-send "$BEEPER_CHAT_TITLE" "$BEEPER_TEXT"'
+  beeper watch --chat "On-call" --webhook http://localhost:8080/beeper`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if execCmd == "" && webhook == "" {
+				return fmt.Errorf("give a hook with --exec or --webhook")
+			}
+
+			filter := watch.Filter{Networks: splitList(networks), IncludeOwn: includeOwn}
+			if match != "" {
+				re, err := regexp.Compile(match)
+				if err != nil {
+					return fmt.Errorf("invalid --match pattern: %w", err)
+				}
+				filter.Match = re
+			}
+
+			var hooks []watch.Hook
+			if execCmd != "" {
+				hooks = append(hooks, watch.ExecHook{Command: execCmd})
+			}
+			if webhook != "" {
+				if !strings.HasPrefix(webhook, "http://") && !strings.HasPrefix(webhook, "https://") {
+					return fmt.Errorf("--webhook must be an http:// or https:// URL")
+				}
+				hooks = append(hooks, watch.WebhookHook{URL: webhook})
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+
+			var chatIDs []string
+			for _, ref := range chats {
+				id, err := resolveChatRef(cmd, client, ref)
+				if err != nil {
+					return err
+				}
+				if !slices.Contains(chatIDs, id) {
+					chatIDs = append(chatIDs, id)
+				}
+			}
+
+			if name == "" {
+				name = watcherName(match, networks, execCmd, webhook, chatIDs)
+			} else if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+				return fmt.Errorf("invalid --name %q: use letters, digits, dashes and underscores", name)
+			}
+			statePath, err := watch.StatePath(name)
+			if err != nil {
+				return err
+			}
+			state, err := watch.LoadState(statePath)
+			if err != nil {
+				return err
+			}
+
+			params := api.FollowParams{ChatIDs: chatIDs, AccountIDs: accountIDs(), NoPush: noPush}
+			if catchUp > 0 && !state.LastSeen.IsZero() {
+				params.Since = state.LastSeen
+				if oldest := time.Now().Add(-catchUp); params.Since.Before(oldest) {
+					params.Since = oldest
+				}
+			}
+
+			stderr := cmd.ErrOrStderr()
+			_, _ = fmt.Fprintf(stderr, "Watching %s (state: %s). Press Ctrl-C to stop.\n", describeScope(chatIDs), statePath)
+
+			w := &watch.Watcher{Filter: filter, Hooks: hooks, State: state, Senders: api.NewSenders(client.Chats())}
+			for ev, err := range client.Messages().Follow(ctx, params) {
+				if ctx.Err() != nil {
+					break
+				}
+				if err != nil {
+					_, _ = fmt.Fprintf(stderr, "warning: %v\n", err)
+					continue
+				}
+				ran, err := w.Handle(ctx, ev)
+				if ran {
+					title := ev.ChatID
+					if ev.Chat != nil {
+						title = ev.Chat.Title
+					}
+					_, _ = fmt.Fprintf(stderr, "[%s] %s: %s\n", formatTime(ev.Timestamp), title, truncate(ev.Text, 60))
+				}
+				if err != nil && ctx.Err() == nil {
+					_, _ = fmt.Fprintf(stderr, "warning: hook failed for message %s: %v\n", ev.ID, err)
+				}
+			}
+
+			if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&match, "match", "", "Regular expression the message text must match")
+	cmd.Flags().StringArrayVar(&chats, "chat", nil, "Only watch this chat (ID or name, repeatable)")
+	cmd.Flags().StringVar(&networks, "network", "", "Only watch these networks or account IDs, comma-separated")
+	cmd.Flags().StringVar(&execCmd, "exec", "", "Shell command to run for each matching message")
+	cmd.Flags().StringVar(&webhook, "webhook", "", "URL to POST each matching message to")
+	cmd.Flags().StringVar(&name, "name", "", "Watcher name for its saved state (default: derived from the filters)")
+	cmd.Flags().BoolVar(&includeOwn, "include-own", false, "Also match messages you sent")
+	cmd.Flags().DurationVar(&catchUp, "catch-up", time.Hour, "On restart, handle messages missed up to this long ago (0 to disable)")
+	cmd.Flags().BoolVar(&noPush, "no-push", false, "Always poll, even if Beeper Desktop offers an event stream")
+
+	return cmd
+}
+
+// watcherName derives a stable state name from a watcher's configuration.
+func watcherName(match, networks, execCmd, webhook string, chatIDs []string) string {
+	h := sha256.New()
+	for _, part := range append([]string{match, networks, execCmd, webhook}, chatIDs...) {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return "watch-" + hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+func describeScope(chatIDs []string) string {
+	switch len(chatIDs) {
+	case 0:
+		return "all chats"
+	case 1:
+		return "1 chat"
+	}
+	return fmt.Sprintf("%d chats", len(chatIDs))
+}
@@ -0,0 +1,162 @@
+// Package compose gets message text from stdin, files or an editor.
+package compose
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrEmpty is returned when the composed message has no text.
+var ErrEmpty = errors.New("aborting: empty message")
+
+// commentPrefix starts lines that are dropped from an edited message.
+const commentPrefix = "#"
+
+// Read returns text from r with trailing newlines removed, as when a
+// message is piped in with `echo`.
+func Read(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read message: %w", err)
+	}
+	text := strings.TrimRight(string(data), "\r\n")
+	if strings.TrimSpace(text) == "" {
+		return "", ErrEmpty
+	}
+	return text, nil
+}
+
+// ReadFile returns the text of a file, like Read.
+func ReadFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read message: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return Read(f)
+}
+
+// ContextLine is a recent message shown as a comment while editing.
+type ContextLine struct {
+	Time   string
+	Sender string
+	Text   string
+}
+
+// Template builds the editor buffer: the initial text, then instructions
+// and the recent messages as comments.
+func Template(initial, chatTitle string, recent []ContextLine) string {
+	var b strings.Builder
+	b.WriteString(initial)
+	if initial != "" && !strings.HasSuffix(initial, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString("# Write your message above. Lines starting with '#' are ignored,\n")
+	b.WriteString("# and an empty message aborts the send.\n")
+	if len(recent) > 0 {
+		b.WriteString("#\n")
+		fmt.Fprintf(&b, "# Recent messages in %s:\n", chatTitle)
+		for _, l := range recent {
+			lines := strings.Split(strings.TrimRight(l.Text, "\n"), "\n")
+			fmt.Fprintf(&b, "#   [%s] %s: %s\n", l.Time, l.Sender, lines[0])
+			for _, more := range lines[1:] {
+				fmt.Fprintf(&b, "#       %s\n", more)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Strip removes comment lines and surrounding blank lines from an edited
+// buffer. It returns ErrEmpty if nothing is left.
+func Strip(buffer string) (string, error) {
+	var kept []string
+	scanner := bufio.NewScanner(strings.NewReader(buffer))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, commentPrefix) {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t"))
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	text := strings.Trim(strings.Join(kept, "\n"), "\n")
+	if strings.TrimSpace(text) == "" {
+		return "", ErrEmpty
+	}
+	return text, nil
+}
+
+// Editor returns the user's editor command from $VISUAL or $EDITOR, falling
+// back to vi (notepad on Windows).
+func Editor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			return v
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// Edit opens editor on a temporary file holding buffer and returns the
+// stripped result once the editor exits. The editor command may include
+// arguments, e.g. "code --wait".
+func Edit(ctx context.Context, editor, buffer string) (string, error) {
+	f, err := os.CreateTemp("", "BEEPER_MESSAGE_*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create message file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+
+	if _, err := f.WriteString(buffer); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("failed to write message file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write message file: %w", err)
+	}
+
+	if err := EditFile(ctx, editor, path); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read message file: %w", err)
+	}
+	return Strip(string(data))
+}
+
+// EditFile opens editor on path in place and waits for it to exit.
+func EditFile(ctx context.Context, editor, path string) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", editor+` "`+path+`"`)
+	} else {
+		// Let the shell split the editor command, as git does.
+		c = exec.CommandContext(ctx, "sh", "-c", editor+` "$1"`, editor, path)
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
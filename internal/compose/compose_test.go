@@ -0,0 +1,81 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	got, err := Read(strings.NewReader("line one\n\nline two\n\n"))
+	if err != nil || got != "line one\n\nline two" {
+		t.Errorf("Read() = %q, %v", got, err)
+	}
+	if _, err := Read(strings.NewReader(" \n\n")); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Read(blank) error = %v, want ErrEmpty", err)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "msg.txt")
+	_ = os.WriteFile(path, []byte("hello\r\n"), 0o600)
+	if got, err := ReadFile(path); err != nil || got != "hello" {
+		t.Errorf("ReadFile() = %q, %v", got, err)
+	}
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ReadFile() should fail for a missing file")
+	}
+}
+
+func TestTemplateRoundTrip(t *testing.T) {
+	buf := Template("draft", "Team", []ContextLine{
+		{Time: "9:00 AM", Sender: "Alice", Text: "standup?\nin 5"},
+		{Time: "9:01 AM", Sender: "You", Text: "yes"},
+	})
+	for _, want := range []string{"draft\n\n# Write your message above", "# Recent messages in Team:", "#   [9:00 AM] Alice: standup?\n#       in 5\n"} {
+		if !strings.Contains(buf, want) {
+			t.Errorf("Template() missing %q:\n%s", want, buf)
+		}
+	}
+
+	// Leaving the buffer as-is sends the initial text.
+	if got, err := Strip(buf); err != nil || got != "draft" {
+		t.Errorf("Strip(Template()) = %q, %v", got, err)
+	}
+	// Without initial text, an untouched buffer aborts.
+	if _, err := Strip(Template("", "Team", nil)); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Strip(empty template) error = %v, want ErrEmpty", err)
+	}
+}
+
+func TestStrip(t *testing.T) {
+	got, err := Strip("\n\nHi all,  \n# note to self\n\nsee below\r\n\n# end\n")
+	if err != nil || got != "Hi all,\n\nsee below" {
+		t.Errorf("Strip() = %q, %v", got, err)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	// A fake editor that prepends a line to the file it is given.
+	script := filepath.Join(t.TempDir(), "fake-editor")
+	_ = os.WriteFile(script, []byte("#!/bin/sh\n{ echo \"$1 says hi\"; cat \"$2\"; } > \"$2.new\" && mv \"$2.new\" \"$2\"\n"), 0o700)
+
+	got, err := Edit(context.Background(), script+" bot", Template("", "Team", nil))
+	if err != nil || got != "bot says hi" {
+		t.Errorf("Edit() = %q, %v", got, err)
+	}
+
+	if _, err := Edit(context.Background(), "true", Template("", "Team", nil)); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Edit() with untouched buffer error = %v, want ErrEmpty", err)
+	}
+	if _, err := Edit(context.Background(), "false", "x"); err == nil || errors.Is(err, ErrEmpty) {
+		t.Errorf("Edit() with failing editor error = %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ConfigFile is the name of the settings file inside ConfigDir.
+const ConfigFile = "config.json"
+
+// Config holds user settings read from ConfigFile.
+type Config struct {
+	// APIURL is the Beeper Desktop API address: http(s)://host:port or
+	// unix:///path/to/socket.
+	APIURL string `json:"api_url,omitempty"`
+	// CACert is a PEM bundle trusted for https API URLs.
+	CACert string `json:"ca_cert,omitempty"`
+	// DefaultProfile is the profile used when --profile and BEEPER_PROFILE
+	// are unset.
+	DefaultProfile string `json:"default_profile,omitempty"`
+	// KeyringBackend selects where tokens are stored (see secrets.Backends).
+	KeyringBackend string `json:"keyring_backend,omitempty"`
+	// TokenCommand is a shell command whose stdout is used as the API token,
+	// e.g. a password-manager CLI. It takes precedence over the keyring.
+	TokenCommand string `json:"token_command,omitempty"`
+}
+
+// Path returns the location of the config file.
+func Path() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ConfigFile), nil
+}
+
+// Load reads the config file. A missing file yields an empty Config.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads a config from path. A missing file yields an empty Config.
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the config file, creating ConfigDir if needed.
+func (c *Config) Save() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return c.SaveFile(path)
+}
+
+// SaveFile writes the config to path, creating its directory if needed.
+func (c *Config) SaveFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
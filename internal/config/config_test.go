@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFileMissing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	if cfg.APIURL != "" {
+		t.Errorf("APIURL = %q, want empty", cfg.APIURL)
+	}
+}
+
+func TestConfigRoundtrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", ConfigFile)
+	want := &Config{APIURL: "unix:///run/beeper.sock", CACert: "/etc/ca.pem", DefaultProfile: "work"}
+
+	if err := want.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile() error: %v", err)
+	}
+	got, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("LoadFile() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFile)
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("LoadFile() should fail on invalid JSON")
+	}
+}
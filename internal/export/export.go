@@ -0,0 +1,280 @@
+// Package export writes chat histories as NDJSON, Markdown, HTML, CSV or
+// mbox archives.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+// Supported formats.
+const (
+	FormatJSONL    = "jsonl"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatCSV      = "csv"
+	FormatMbox     = "mbox"
+)
+
+// Formats lists the supported formats.
+var Formats = []string{FormatJSONL, FormatMarkdown, FormatHTML, FormatCSV, FormatMbox}
+
+// ParseFormat normalizes a format name, accepting common aliases.
+func ParseFormat(name string) (string, error) {
+	switch strings.ToLower(name) {
+	case "jsonl", "ndjson", "json":
+		return FormatJSONL, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	case "csv":
+		return FormatCSV, nil
+	case "mbox":
+		return FormatMbox, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want %s)", name, strings.Join(Formats, ", "))
+}
+
+// FormatForPath guesses the format from a file extension, or returns "".
+func FormatForPath(path string) string {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return ""
+	}
+	format, err := ParseFormat(ext)
+	if err != nil {
+		return ""
+	}
+	return format
+}
+
+// Transcript is a chat and its messages, oldest first.
+type Transcript struct {
+	Chat     api.Chat
+	Messages []api.Message
+	Since    time.Time
+	Until    time.Time
+	Exported time.Time
+
+	byID map[string]*api.Message
+}
+
+// Range bounds the messages Collect keeps. Zero values are unbounded.
+type Range struct {
+	Since time.Time // inclusive
+	Until time.Time // exclusive
+}
+
+// Collect pages through a chat's history and returns the messages inside r.
+// The API returns newest messages first, but not strictly in order, so
+// paging stops only once a whole page is older than r.Since.
+func Collect(ctx context.Context, client *api.Client, chatID string, r Range) (*Transcript, error) {
+	chat, err := client.Chats().Get(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []api.Message
+	params := api.ListMessagesParams{Direction: "before"}
+	seen := map[string]bool{}
+	for {
+		page, err := client.Messages().List(ctx, chatID, params)
+		if err != nil {
+			return nil, err
+		}
+		older := 0
+		for _, m := range page.Items {
+			switch {
+			case !r.Since.IsZero() && m.Timestamp.Before(r.Since):
+				older++
+			case !r.Until.IsZero() && !m.Timestamp.Before(r.Until):
+			default:
+				messages = append(messages, m)
+			}
+		}
+		if len(page.Items) > 0 && older == len(page.Items) {
+			break
+		}
+		// Stop on an exhausted history, or a cursor that would loop forever.
+		if !page.HasMore || page.Cursor == "" || seen[page.Cursor] {
+			break
+		}
+		seen[page.Cursor] = true
+		params.Cursor = page.Cursor
+	}
+
+	return NewTranscript(*chat, messages, r), nil
+}
+
+// NewTranscript sorts messages oldest first and indexes participants and
+// messages for name and reply lookups.
+func NewTranscript(chat api.Chat, messages []api.Message, r Range) *Transcript {
+	slices.SortStableFunc(messages, func(a, b api.Message) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	t := &Transcript{
+		Chat:     chat,
+		Messages: messages,
+		Since:    r.Since,
+		Until:    r.Until,
+		Exported: time.Now(),
+		byID:     map[string]*api.Message{},
+	}
+	for i := range t.Messages {
+		t.byID[t.Messages[i].ID] = &t.Messages[i]
+	}
+	return t
+}
+
+// SenderName returns the display name of a message's sender, preferring the
+// chat's participant list.
+func (t *Transcript) SenderName(m *api.Message) string {
+	return api.SenderName(*m, &t.Chat)
+}
+
+// ReplyTarget returns the message m replies to, filling in the text from
+// the transcript when the API only sent an ID. It returns nil for messages
+// that aren't replies.
+func (t *Transcript) ReplyTarget(m *api.Message) *api.Message {
+	if m.ReplyTo == nil || m.ReplyTo.ID == "" && m.ReplyTo.Text == "" {
+		return nil
+	}
+	if m.ReplyTo.Text == "" {
+		if orig, ok := t.byID[m.ReplyTo.ID]; ok {
+			return orig
+		}
+	}
+	return m.ReplyTo
+}
+
+// Write renders t in format.
+func Write(w io.Writer, format string, t *Transcript) error {
+	switch format {
+	case FormatJSONL:
+		return writeJSONL(w, t)
+	case FormatMarkdown:
+		return writeMarkdown(w, t)
+	case FormatHTML:
+		return writeHTML(w, t)
+	case FormatCSV:
+		return writeCSV(w, t)
+	case FormatMbox:
+		return writeMbox(w, t)
+	}
+	_, err := ParseFormat(format)
+	return err
+}
+
+func writeJSONL(w io.Writer, t *Transcript) error {
+	enc := json.NewEncoder(w)
+	for _, m := range t.Messages {
+		if err := enc.Encode(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, t *Transcript) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"id", "timestamp", "sender_id", "sender", "is_me", "text", "reply_to_id", "reply_to_sender", "reply_to_text"})
+	for i := range t.Messages {
+		m := &t.Messages[i]
+		row := []string{
+			m.ID,
+			m.Timestamp.UTC().Format(time.RFC3339),
+			m.SenderID,
+			t.SenderName(m),
+			strconv.FormatBool(m.IsMe),
+			m.Text,
+			"", "", "",
+		}
+		if reply := t.ReplyTarget(m); reply != nil {
+			row[6], row[7], row[8] = reply.ID, t.SenderName(reply), reply.Text
+		}
+		_ = cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeMarkdown(w io.Writer, t *Transcript) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", markdownEscape(t.Title()))
+	fmt.Fprintf(&b, "_%s_\n", t.Description())
+
+	var day string
+	for i := range t.Messages {
+		m := &t.Messages[i]
+		local := m.Timestamp.Local()
+		if d := local.Format("Monday, January 2, 2006"); d != day {
+			day = d
+			fmt.Fprintf(&b, "\n## %s\n", day)
+		}
+		fmt.Fprintf(&b, "\n**%s** · %s  \n", markdownEscape(t.SenderName(m)), local.Format("15:04"))
+		if reply := t.ReplyTarget(m); reply != nil {
+			fmt.Fprintf(&b, "> ↪ **%s**: %s\n>\n", markdownEscape(t.SenderName(reply)), oneLine(reply.Text, 120))
+		}
+		if m.Text != "" {
+			// Two trailing spaces keep line breaks inside a message.
+			b.WriteString(strings.ReplaceAll(m.Text, "\n", "  \n"))
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Title is the chat title, or its ID when untitled.
+func (t *Transcript) Title() string {
+	if t.Chat.Title != "" {
+		return t.Chat.Title
+	}
+	return t.Chat.ID
+}
+
+// Description summarizes the network, message count and date range.
+func (t *Transcript) Description() string {
+	parts := []string{}
+	if t.Chat.Network != "" {
+		parts = append(parts, t.Chat.Network)
+	}
+	parts = append(parts, fmt.Sprintf("%d messages", len(t.Messages)))
+	if n := len(t.Messages); n > 0 {
+		first := t.Messages[0].Timestamp.Local().Format("Jan 2, 2006")
+		last := t.Messages[n-1].Timestamp.Local().Format("Jan 2, 2006")
+		if first == last {
+			parts = append(parts, first)
+		} else {
+			parts = append(parts, first+" – "+last)
+		}
+	}
+	parts = append(parts, "exported "+t.Exported.Local().Format("Jan 2, 2006 15:04"))
+	return strings.Join(parts, " · ")
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`).Replace(s)
+}
+
+// oneLine collapses whitespace and shortens s to max runes.
+func oneLine(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > max {
+		return string(r[:max-1]) + "…"
+	}
+	return s
+}
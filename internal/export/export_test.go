@@ -0,0 +1,199 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+var base = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func sampleTranscript() *Transcript {
+	chat := api.Chat{ID: "!c1:beeper.com", Title: "Acme <Support>", Network: "WhatsApp", Participants: &api.ParticipantList{Items: []api.Participant{
+		{ID: "@alice:wa", FullName: "Alice Smith"},
+		{ID: "@me:beeper.com", FullName: "Me", IsSelf: true},
+	}}}
+	// Deliberately out of order: transcripts are sorted oldest first.
+	messages := []api.Message{
+		{ID: "m2", SenderID: "@me:beeper.com", IsMe: true, Text: "Sure, <b>tomorrow</b>\nFrom 9am", Timestamp: base.Add(time.Hour), ReplyTo: &api.Message{ID: "m1"}},
+		{ID: "m1", SenderID: "@alice:wa", Text: "Can we talk?", Timestamp: base},
+		{ID: "m3", SenderID: "@bob:wa", Sender: "Bob", Text: "late, \"quoted\", with commas", Timestamp: base.Add(2 * time.Hour)},
+	}
+	return NewTranscript(chat, messages, Range{})
+}
+
+func render(t *testing.T, format string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, format, sampleTranscript()); err != nil {
+		t.Fatalf("Write(%s) error: %v", format, err)
+	}
+	return buf.String()
+}
+
+func TestTranscriptNamesAndReplies(t *testing.T) {
+	tr := sampleTranscript()
+	if tr.Messages[0].ID != "m1" {
+		t.Fatalf("messages not sorted oldest first: %s", tr.Messages[0].ID)
+	}
+	if got := tr.SenderName(&tr.Messages[0]); got != "Alice Smith" {
+		t.Errorf("SenderName() = %q, want participant name", got)
+	}
+	if got := tr.SenderName(&tr.Messages[2]); got != "Bob" {
+		t.Errorf("SenderName() = %q, want message sender", got)
+	}
+	reply := tr.ReplyTarget(&tr.Messages[1])
+	if reply == nil || reply.Text != "Can we talk?" {
+		t.Errorf("ReplyTarget() = %+v, want the original message", reply)
+	}
+	if tr.ReplyTarget(&tr.Messages[0]) != nil {
+		t.Error("ReplyTarget() should be nil for a non-reply")
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(render(t, FormatJSONL)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	var m api.Message
+	if err := json.Unmarshal([]byte(lines[1]), &m); err != nil {
+		t.Fatalf("line is not a message: %v", err)
+	}
+	if m.ID != "m2" || m.ReplyTo == nil {
+		t.Errorf("line 2 = %+v, want m2 with reply", m)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(render(t, FormatCSV))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want header + 3", len(records))
+	}
+	if got := records[2]; got[3] != "You" || got[6] != "m1" || got[8] != "Can we talk?" {
+		t.Errorf("reply row = %v", got)
+	}
+	if got := records[3][5]; got != `late, "quoted", with commas` {
+		t.Errorf("text = %q", got)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	out := render(t, FormatMarkdown)
+	for _, want := range []string{
+		"# Acme <Support>\n",
+		"**Alice Smith** · ",
+		"> ↪ **Alice Smith**: Can we talk?",
+		"Sure, <b>tomorrow</b>  \nFrom 9am",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteHTMLEscapes(t *testing.T) {
+	out := render(t, FormatHTML)
+	if strings.Contains(out, "<b>tomorrow</b>") {
+		t.Error("message text must be escaped")
+	}
+	for _, want := range []string{"<title>Acme &lt;Support&gt;</title>", `class="message me"`, `<div class="reply"><strong>Alice Smith</strong>: Can we talk?</div>`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML missing %q", want)
+		}
+	}
+	if strings.Contains(out, "https://") {
+		t.Error("HTML export should not load external assets")
+	}
+}
+
+func TestWriteMbox(t *testing.T) {
+	out := render(t, FormatMbox)
+	if n := strings.Count(out, "\nFrom: "); n != 3 {
+		t.Errorf("got %d messages, want 3", n)
+	}
+	for _, want := range []string{
+		"From alice.wa@beeper.invalid Sat Mar  1 12:00:00 2025\n",
+		`From: "Alice Smith" <alice.wa@beeper.invalid>`,
+		"In-Reply-To: <m1@beeper.invalid>",
+		"\n>From 9am\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mbox missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"md": FormatMarkdown, "NDJSON": FormatJSONL, "mbox": FormatMbox} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("ParseFormat(pdf) should fail")
+	}
+	if got := FormatForPath("out/chat.html"); got != FormatHTML {
+		t.Errorf("FormatForPath() = %q, want html", got)
+	}
+}
+
+func TestCollectStopsAtSince(t *testing.T) {
+	pages := 0
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chats/c1":
+			testutil.JSONResponse(w, http.StatusOK, `{"id":"c1","title":"Chat"}`)
+		case "/v1/chats/c1/messages":
+			pages++
+			switch r.URL.Query().Get("cursor") {
+			case "":
+				testutil.JSONResponse(w, http.StatusOK, `{"items":[
+					{"id":"m4","timestamp":"2025-03-04T00:00:00Z"},
+					{"id":"m3","timestamp":"2025-03-03T00:00:00Z"}],"hasMore":true,"cursor":"p2"}`)
+			case "p2":
+				// m1 is out of order: m2b, after it, is still in range.
+				testutil.JSONResponse(w, http.StatusOK, `{"items":[
+					{"id":"m2","timestamp":"2025-03-02T00:00:00Z"},
+					{"id":"m1","timestamp":"2025-03-01T00:00:00Z"},
+					{"id":"m2b","timestamp":"2025-03-02T12:00:00Z"}],"hasMore":true,"cursor":"p3"}`)
+			case "p3":
+				testutil.JSONResponse(w, http.StatusOK, `{"items":[
+					{"id":"m0","timestamp":"2025-02-28T00:00:00Z"}],"hasMore":true,"cursor":"p4"}`)
+			default:
+				t.Errorf("paged past --since")
+			}
+		}
+	})
+
+	client := api.NewClient(server.URL, "test-token")
+	tr, err := Collect(context.Background(), client, "c1", Range{
+		Since: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+
+	var ids []string
+	for _, m := range tr.Messages {
+		ids = append(ids, m.ID)
+	}
+	if strings.Join(ids, ",") != "m2,m2b,m3" {
+		t.Errorf("messages = %v, want [m2 m2b m3]", ids)
+	}
+	if pages != 3 {
+		t.Errorf("fetched %d pages, want 3", pages)
+	}
+}
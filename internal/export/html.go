@@ -0,0 +1,179 @@
+package export
+
+import (
+	"html/template"
+	"io"
+)
+
+type htmlMessage struct {
+	Day       string
+	Time      string
+	Sender    string
+	Text      string
+	IsMe      bool
+	ReplyFrom string
+	ReplyText string
+}
+
+type htmlPage struct {
+	Title       string
+	Description string
+	Messages    []htmlMessage
+}
+
+func writeHTML(w io.Writer, t *Transcript) error {
+	page := htmlPage{Title: t.Title(), Description: t.Description()}
+	var day string
+	for i := range t.Messages {
+		m := &t.Messages[i]
+		local := m.Timestamp.Local()
+		hm := htmlMessage{
+			Time:   local.Format("15:04"),
+			Sender: t.SenderName(m),
+			Text:   m.Text,
+			IsMe:   m.IsMe,
+		}
+		if d := local.Format("Monday, January 2, 2006"); d != day {
+			day = d
+			hm.Day = d
+		}
+		if reply := t.ReplyTarget(m); reply != nil {
+			hm.ReplyFrom = t.SenderName(reply)
+			hm.ReplyText = oneLine(reply.Text, 160)
+		}
+		page.Messages = append(page.Messages, hm)
+	}
+	return htmlTemplate.Execute(w, page)
+}
+
+// htmlTemplate is a self-contained page (no external assets) using the
+// colors of the auth setup pages.
+var htmlTemplate = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    <style>
+        :root {
+            --bg-deep: #050508;
+            --bg-card: rgba(12, 12, 18, 0.8);
+            --border: rgba(255, 255, 255, 0.06);
+            --text: #f0f0f5;
+            --text-muted: #8888a0;
+            --text-dim: #4a4a5c;
+            --accent: #6953f2;
+            --gradient: linear-gradient(225deg, #6953f2, #0c52f9);
+            --accent-glow: rgba(105, 83, 242, 0.2);
+        }
+
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+
+        body {
+            font-family: 'DM Sans', -apple-system, BlinkMacSystemFont, sans-serif;
+            background: var(--bg-deep);
+            color: var(--text);
+            padding: 2rem 1.5rem;
+        }
+
+        .container {
+            max-width: 760px;
+            margin: 0 auto;
+        }
+
+        header {
+            padding-bottom: 1.25rem;
+            margin-bottom: 1.5rem;
+            border-bottom: 1px solid var(--border);
+        }
+
+        h1 {
+            font-size: 1.5rem;
+            font-weight: 600;
+        }
+
+        .meta {
+            margin-top: 0.35rem;
+            font-family: 'JetBrains Mono', ui-monospace, monospace;
+            font-size: 0.75rem;
+            color: var(--text-muted);
+        }
+
+        .day {
+            margin: 1.75rem 0 0.75rem;
+            text-align: center;
+            font-size: 0.75rem;
+            color: var(--text-dim);
+            text-transform: uppercase;
+            letter-spacing: 0.08em;
+        }
+
+        .message {
+            max-width: 85%;
+            margin-bottom: 0.6rem;
+            padding: 0.7rem 0.95rem;
+            background: var(--bg-card);
+            border: 1px solid var(--border);
+            border-radius: 16px;
+        }
+
+        .message.me {
+            margin-left: auto;
+            background: var(--accent-glow);
+            border-color: rgba(105, 83, 242, 0.35);
+        }
+
+        .sender {
+            font-size: 0.8rem;
+            font-weight: 600;
+            color: var(--accent);
+        }
+
+        .time {
+            margin-left: 0.4rem;
+            font-size: 0.7rem;
+            color: var(--text-dim);
+        }
+
+        .reply {
+            margin: 0.4rem 0;
+            padding-left: 0.6rem;
+            border-left: 2px solid var(--accent);
+            font-size: 0.8rem;
+            color: var(--text-muted);
+        }
+
+        .text {
+            margin-top: 0.2rem;
+            white-space: pre-wrap;
+            word-wrap: break-word;
+            line-height: 1.45;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <header>
+            <h1>{{.Title}}</h1>
+            <div class="meta">{{.Description}}</div>
+        </header>
+{{- range .Messages}}
+{{- if .Day}}
+        <div class="day">{{.Day}}</div>
+{{- end}}
+        <div class="message{{if .IsMe}} me{{end}}">
+            <span class="sender">{{.Sender}}</span><span class="time">{{.Time}}</span>
+{{- if .ReplyFrom}}
+            <div class="reply"><strong>{{.ReplyFrom}}</strong>: {{.ReplyText}}</div>
+{{- end}}
+            <div class="text">{{.Text}}</div>
+        </div>
+{{- end}}
+    </div>
+</body>
+</html>
+`))
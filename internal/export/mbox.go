@@ -0,0 +1,69 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+// mboxFromLine matches body lines that mboxrd quoting must escape.
+var mboxFromLine = regexp.MustCompile(`^>*From `)
+
+// addressUnsafe matches characters not allowed in the local part of the
+// synthetic sender addresses.
+var addressUnsafe = regexp.MustCompile(`[^A-Za-z0-9._+-]+`)
+
+// writeMbox writes one mboxrd message per chat message so transcripts can be
+// opened in a mail client. Replies are threaded with In-Reply-To.
+func writeMbox(w io.Writer, t *Transcript) error {
+	bw := bufio.NewWriter(w)
+	subject := mime.QEncoding.Encode("utf-8", t.Title())
+
+	for i := range t.Messages {
+		m := &t.Messages[i]
+		from := mailAddress(t, m)
+		date := m.Timestamp.UTC()
+
+		fmt.Fprintf(bw, "From %s %s\n", from.Address, date.Format(time.ANSIC))
+		fmt.Fprintf(bw, "From: %s\n", from.String())
+		fmt.Fprintf(bw, "Date: %s\n", date.Format(time.RFC1123Z))
+		fmt.Fprintf(bw, "Subject: %s\n", subject)
+		fmt.Fprintf(bw, "Message-ID: %s\n", messageID(m.ID))
+		if reply := t.ReplyTarget(m); reply != nil && reply.ID != "" {
+			fmt.Fprintf(bw, "In-Reply-To: %s\n", messageID(reply.ID))
+		}
+		fmt.Fprintf(bw, "X-Beeper-Chat-ID: %s\n", t.Chat.ID)
+		bw.WriteString("MIME-Version: 1.0\n")
+		bw.WriteString("Content-Type: text/plain; charset=utf-8\n")
+		bw.WriteString("Content-Transfer-Encoding: 8bit\n\n")
+
+		for _, line := range strings.Split(strings.ReplaceAll(m.Text, "\r\n", "\n"), "\n") {
+			if mboxFromLine.MatchString(line) {
+				line = ">" + line
+			}
+			bw.WriteString(line)
+			bw.WriteString("\n")
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+func mailAddress(t *Transcript, m *api.Message) *mail.Address {
+	local := strings.Trim(addressUnsafe.ReplaceAllString(m.SenderID, "."), ".")
+	if local == "" {
+		local = "unknown"
+	}
+	return &mail.Address{Name: t.SenderName(m), Address: local + "@beeper.invalid"}
+}
+
+func messageID(id string) string {
+	return "<" + strings.Trim(addressUnsafe.ReplaceAllString(id, "."), ".") + "@beeper.invalid>"
+}
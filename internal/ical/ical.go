@@ -0,0 +1,143 @@
+// Package ical reads and writes iCalendar (RFC 5545) calendars of simple
+// events.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ProdID identifies the calendar's producer.
+const ProdID = "-//beeper-cli//reminders//EN"
+
+// Event is a VEVENT. Zero-valued optional fields are omitted.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Categories  []string
+	Start       time.Time
+	Duration    time.Duration
+	Stamp       time.Time
+	// Extra holds non-standard properties such as X-BEEPER-CHAT-ID.
+	Extra map[string]string
+	// Err is set by Parse when a time in the event could not be read, such
+	// as one in an unknown time zone. Write ignores it.
+	Err error
+}
+
+// Calendar is a VCALENDAR holding events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Write renders cal with CRLF line endings and folded long lines.
+func Write(w io.Writer, cal Calendar) error {
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(fold(name + ":" + value))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", ProdID)
+	line("CALSCALE", "GREGORIAN")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", Escape(cal.Name))
+	}
+	for _, ev := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", Escape(ev.UID))
+		stamp := ev.Stamp
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		line("DTSTAMP", FormatTime(stamp))
+		line("DTSTART", FormatTime(ev.Start))
+		if ev.Duration > 0 {
+			line("DURATION", formatDuration(ev.Duration))
+		}
+		line("SUMMARY", Escape(ev.Summary))
+		if ev.Description != "" {
+			line("DESCRIPTION", Escape(ev.Description))
+		}
+		if ev.URL != "" {
+			line("URL", ev.URL)
+		}
+		if len(ev.Categories) > 0 {
+			escaped := make([]string, len(ev.Categories))
+			for i, c := range ev.Categories {
+				escaped[i] = Escape(c)
+			}
+			line("CATEGORIES", strings.Join(escaped, ","))
+		}
+		for _, name := range sortedKeys(ev.Extra) {
+			line(name, Escape(ev.Extra[name]))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// FormatTime formats t as a UTC date-time, e.g. 20250305T143000Z.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// Escape escapes a TEXT value.
+func Escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// fold splits a content line into 75-octet pieces joined by CRLF and a
+// space, without breaking UTF-8 sequences.
+func fold(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > limit {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
+
+func formatDuration(d time.Duration) string {
+	s := "PT"
+	if h := int(d / time.Hour); h > 0 {
+		s += fmt.Sprintf("%dH", h)
+	}
+	if m := int(d % time.Hour / time.Minute); m > 0 {
+		s += fmt.Sprintf("%dM", m)
+	}
+	if s == "PT" {
+		s += fmt.Sprintf("%dS", int(d/time.Second))
+	}
+	return s
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,67 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	start := time.Date(2025, time.March, 5, 9, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	var b strings.Builder
+	err := Write(&b, Calendar{Name: "Beeper reminders", Events: []Event{{
+		UID:         "chat-1@beeper-cli",
+		Summary:     "Reply to Ann, Bob; soon",
+		Description: "line one\nline two",
+		URL:         "https://example.com/x",
+		Categories:  []string{"slack"},
+		Start:       start,
+		Duration:    15 * time.Minute,
+		Stamp:       start,
+		Extra:       map[string]string{"X-B": "2", "X-A": "1"},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + ProdID,
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Beeper reminders",
+		"BEGIN:VEVENT",
+		"UID:chat-1@beeper-cli",
+		"DTSTAMP:20250305T140000Z",
+		"DTSTART:20250305T140000Z",
+		"DURATION:PT15M",
+		`SUMMARY:Reply to Ann\, Bob\; soon`,
+		`DESCRIPTION:line one\nline two`,
+		"URL:https://example.com/x",
+		"CATEGORIES:slack",
+		"X-A:1",
+		"X-B:2",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := b.String(); got != want {
+		t.Errorf("Write() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFold(t *testing.T) {
+	long := "SUMMARY:" + strings.Repeat("é", 60)
+	folded := fold(long)
+	for i, part := range strings.Split(folded, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("line %d is %d octets", i, len(part))
+		}
+		if i > 0 && !strings.HasPrefix(part, " ") {
+			t.Errorf("continuation line %d doesn't start with a space", i)
+		}
+	}
+	if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != long {
+		t.Errorf("unfolding changed the line: %q", unfolded)
+	}
+}
@@ -0,0 +1,202 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Parse reads the VEVENTs of an iCalendar stream. Only the properties Event
+// holds are kept; other X- properties land in Extra. All-day events start at
+// allDayHour local time. An event whose time zone can't be resolved is kept
+// with Err set, so one bad event doesn't fail the calendar.
+func Parse(r io.Reader, allDayHour int) (Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	zones := calendarZones(lines)
+	var (
+		cal    Calendar
+		ev     *Event
+		nested int // depth inside components within an event, like VALARM
+	)
+	for n, line := range lines {
+		name, params, value, ok := splitLine(line)
+		if !ok {
+			continue
+		}
+		isEvent := strings.EqualFold(value, "VEVENT")
+		switch {
+		case ev != nil && name == "BEGIN" && !isEvent:
+			nested++
+		case nested > 0:
+			if name == "END" {
+				nested--
+			}
+		case name == "BEGIN" && isEvent:
+			ev = &Event{}
+		case name == "END" && isEvent:
+			if ev != nil {
+				cal.Events = append(cal.Events, *ev)
+			}
+			ev = nil
+		case name == "X-WR-CALNAME" && ev == nil:
+			cal.Name = Unescape(value)
+		case ev == nil:
+		case name == "UID":
+			ev.UID = Unescape(value)
+		case name == "SUMMARY":
+			ev.Summary = Unescape(value)
+		case name == "DESCRIPTION":
+			ev.Description = Unescape(value)
+		case name == "URL":
+			ev.URL = value
+		case name == "CATEGORIES":
+			for _, c := range splitList(value) {
+				ev.Categories = append(ev.Categories, Unescape(c))
+			}
+		case name == "DTSTART" || name == "DTSTAMP":
+			t, err := parseTime(value, params, allDayHour, zones)
+			if errors.Is(err, ErrUnknownZone) {
+				if ev.Err == nil {
+					ev.Err = fmt.Errorf("line %d: %w", n+1, err)
+				}
+				continue
+			}
+			if err != nil {
+				return cal, fmt.Errorf("line %d: %w", n+1, err)
+			}
+			if name == "DTSTART" {
+				ev.Start = t
+			} else {
+				ev.Stamp = t
+			}
+		case strings.HasPrefix(name, "X-"):
+			if ev.Extra == nil {
+				ev.Extra = map[string]string{}
+			}
+			ev.Extra[name] = Unescape(value)
+		}
+	}
+	return cal, nil
+}
+
+// Unescape reverses Escape.
+func Unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitList splits a comma-separated value, leaving escaped commas alone.
+func splitList(value string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// unfold joins continuation lines, which start with a space or tab.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// splitLine splits NAME;PARAM=x;PARAM=y:value. Quoted parameter values may
+// contain colons.
+func splitLine(line string) (name string, params map[string]string, value string, ok bool) {
+	inQuote := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuote = !inQuote
+		}
+		if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", false
+	}
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	params = map[string]string{}
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+// parseTime reads a DATE-TIME in UTC, in a TZID zone, or floating (local),
+// or a DATE at allDayHour local time. An unresolvable TZID fails with
+// ErrUnknownZone rather than being read as local time.
+func parseTime(value string, params map[string]string, allDayHour int, zones map[string]string) (time.Time, error) {
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		l, err := loadZone(tzid, zones)
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc = l
+	}
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		d, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+		return time.Date(d.Year(), d.Month(), d.Day(), allDayHour, 0, 0, 0, loc), nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q", value)
+		}
+		return t, nil
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", value)
+	}
+	return t, nil
+}
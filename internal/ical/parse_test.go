@@ -0,0 +1,137 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	start := time.Date(2025, time.March, 5, 14, 0, 0, 0, time.UTC)
+	in := Calendar{Name: "Beeper reminders", Events: []Event{{
+		UID:         "chat-1@beeper-cli",
+		Summary:     "Reply to Ann, Bob; soon",
+		Description: "line one\nline two " + strings.Repeat("x", 80),
+		URL:         "beeper://chat/chat-1",
+		Categories:  []string{"slack", "a,b"},
+		Start:       start,
+		Stamp:       start,
+		Extra:       map[string]string{ChatIDProperty: "!room:beeper.com"},
+	}}}
+	var b strings.Builder
+	if err := Write(&b, in); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Parse(strings.NewReader(b.String()), 9)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if got.Name != in.Name || len(got.Events) != 1 {
+		t.Fatalf("Parse() = %+v", got)
+	}
+	ev, want := got.Events[0], in.Events[0]
+	if ev.UID != want.UID || ev.Summary != want.Summary || ev.Description != want.Description || ev.URL != want.URL {
+		t.Errorf("event = %+v, want %+v", ev, want)
+	}
+	if !ev.Start.Equal(start) || !ev.Stamp.Equal(start) {
+		t.Errorf("Start, Stamp = %v, %v; want %v", ev.Start, ev.Stamp, start)
+	}
+	if strings.Join(ev.Categories, "|") != "slack|a,b" {
+		t.Errorf("Categories = %q", ev.Categories)
+	}
+	if ev.Extra[ChatIDProperty] != "!room:beeper.com" {
+		t.Errorf("Extra = %v", ev.Extra)
+	}
+}
+
+func TestParseTimes(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		`DTSTART;TZID="America/New_York":20250305T090000`,
+		"SUMMARY:zoned",
+		"BEGIN:VALARM",
+		"DESCRIPTION:alarm text",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250306",
+		"SUMMARY:all day",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")
+
+	cal, err := Parse(strings.NewReader(data), 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cal.Events) != 2 {
+		t.Fatalf("got %d events, want 2", len(cal.Events))
+	}
+	if want := time.Date(2025, 3, 5, 9, 0, 0, 0, ny); !cal.Events[0].Start.Equal(want) {
+		t.Errorf("TZID start = %v, want %v", cal.Events[0].Start, want)
+	}
+	if cal.Events[0].Description != "" {
+		t.Errorf("alarm description leaked into the event: %q", cal.Events[0].Description)
+	}
+	if want := time.Date(2025, 3, 6, 9, 0, 0, 0, time.Local); !cal.Events[1].Start.Equal(want) {
+		t.Errorf("all-day start = %v, want %v", cal.Events[1].Start, want)
+	}
+
+	if _, err := Parse(strings.NewReader("BEGIN:VEVENT\nDTSTART:soon\nEND:VEVENT\n"), 9); err == nil {
+		t.Error("Parse() accepted an invalid DTSTART")
+	}
+}
+
+func TestParseTimeZoneNames(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTIMEZONE",
+		"TZID:/custom/Eastern",
+		"X-LIC-LOCATION:America/New_York",
+		"BEGIN:STANDARD",
+		"TZNAME:EST",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=Eastern Standard Time:20250305T090000",
+		"SUMMARY:windows",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=/custom/Eastern:20250305T090000",
+		"SUMMARY:vtimezone",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=Mars Standard Time:20250305T090000",
+		"SUMMARY:unknown",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")
+
+	cal, err := Parse(strings.NewReader(data), 9)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(cal.Events) != 3 {
+		t.Fatalf("got %d events, want 3", len(cal.Events))
+	}
+	want := time.Date(2025, 3, 5, 9, 0, 0, 0, ny)
+	for _, ev := range cal.Events[:2] {
+		if ev.Err != nil || !ev.Start.Equal(want) {
+			t.Errorf("%s: Start = %v, Err = %v; want %v", ev.Summary, ev.Start, ev.Err, want)
+		}
+	}
+	if ev := cal.Events[2]; !errors.Is(ev.Err, ErrUnknownZone) || !ev.Start.IsZero() {
+		t.Errorf("unknown zone: Start = %v, Err = %v; want an unknown time zone error", ev.Start, ev.Err)
+	}
+}
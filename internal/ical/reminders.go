@@ -0,0 +1,54 @@
+package ical
+
+import (
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+// Properties that tie a reminder event back to its chat.
+const (
+	ChatIDProperty  = "X-BEEPER-CHAT-ID"
+	NetworkProperty = "X-BEEPER-NETWORK"
+)
+
+const (
+	summaryPrefix    = "Reply to "
+	reminderDuration = 15 * time.Minute
+)
+
+// ReminderCalendar turns chat reminders into a calendar of 15-minute events.
+// Each description names the chat and network and links to it in Beeper.
+func ReminderCalendar(reminders []api.Reminder, now time.Time) Calendar {
+	cal := Calendar{Name: "Beeper reminders"}
+	for _, r := range reminders {
+		link := api.ChatDeeplink(r.ChatID)
+		desc := []string{"Chat: " + r.Title}
+		ev := Event{
+			UID:      r.ChatID + "@beeper-cli",
+			Summary:  summaryPrefix + r.Title,
+			URL:      link,
+			Start:    r.RemindAt,
+			Duration: reminderDuration,
+			Stamp:    now,
+			Extra:    map[string]string{ChatIDProperty: r.ChatID},
+		}
+		if r.Network != "" {
+			desc = append(desc, "Network: "+r.Network)
+			ev.Categories = []string{r.Network}
+			ev.Extra[NetworkProperty] = r.Network
+		}
+		ev.Description = strings.Join(append(desc, "Open in Beeper: "+link), "\n")
+		cal.Events = append(cal.Events, ev)
+	}
+	return cal
+}
+
+// EventChat returns the chat an event refers to: its X-BEEPER-CHAT-ID when
+// present, and otherwise a title taken from the summary, without the "Reply
+// to " that ReminderCalendar adds.
+func EventChat(ev Event) (chatID, title string) {
+	title = strings.TrimSpace(strings.TrimPrefix(ev.Summary, summaryPrefix))
+	return strings.TrimSpace(ev.Extra[ChatIDProperty]), title
+}
@@ -0,0 +1,41 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+func TestReminderCalendar(t *testing.T) {
+	at := time.Date(2025, time.March, 5, 14, 0, 0, 0, time.UTC)
+	cal := ReminderCalendar([]api.Reminder{
+		{ChatID: "!a:beeper.com", Title: "Alice", Network: "Slack", RemindAt: at},
+		{ChatID: "b", Title: "Bob", RemindAt: at},
+	}, at)
+	if len(cal.Events) != 2 {
+		t.Fatalf("got %d events, want 2", len(cal.Events))
+	}
+
+	ev := cal.Events[0]
+	link := api.ChatDeeplink("!a:beeper.com")
+	for _, s := range []string{"Chat: Alice", "Network: Slack", "Open in Beeper: " + link} {
+		if !strings.Contains(ev.Description, s) {
+			t.Errorf("description %q lacks %q", ev.Description, s)
+		}
+	}
+	if ev.URL != link || ev.Extra[NetworkProperty] != "Slack" {
+		t.Errorf("event = %+v", ev)
+	}
+	if strings.Contains(cal.Events[1].Description, "Network:") {
+		t.Errorf("description without a network = %q", cal.Events[1].Description)
+	}
+
+	if id, title := EventChat(ev); id != "!a:beeper.com" || title != "Alice" {
+		t.Errorf("EventChat() = %q, %q", id, title)
+	}
+	if id, title := EventChat(Event{Summary: "Team standup"}); id != "" || title != "Team standup" {
+		t.Errorf("EventChat() without an ID = %q, %q", id, title)
+	}
+}
@@ -0,0 +1,116 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrUnknownZone is returned for a TZID that names no known time zone.
+var ErrUnknownZone = errors.New("unknown time zone")
+
+// windowsZones maps the Windows time zone names Outlook and Exchange write as
+// TZIDs to IANA zones.
+var windowsZones = map[string]string{
+	"Dateline Standard Time":          "Etc/GMT+12",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Mountain Standard Time":          "America/Denver",
+	"Central Standard Time":           "America/Chicago",
+	"Central America Standard Time":   "America/Guatemala",
+	"Canada Central Standard Time":    "America/Regina",
+	"Central Standard Time (Mexico)":  "America/Mexico_City",
+	"Eastern Standard Time":           "America/New_York",
+	"US Eastern Standard Time":        "America/Indianapolis",
+	"SA Pacific Standard Time":        "America/Bogota",
+	"Atlantic Standard Time":          "America/Halifax",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"Argentina Standard Time":         "America/Buenos_Aires",
+	"UTC":                             "UTC",
+	"GMT Standard Time":               "Europe/London",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Romance Standard Time":           "Europe/Paris",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"FLE Standard Time":               "Europe/Kiev",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"Egypt Standard Time":             "Africa/Cairo",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"Russian Standard Time":           "Europe/Moscow",
+	"Arab Standard Time":              "Asia/Riyadh",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"Iran Standard Time":              "Asia/Tehran",
+	"Pakistan Standard Time":          "Asia/Karachi",
+	"India Standard Time":             "Asia/Calcutta",
+	"Nepal Standard Time":             "Asia/Katmandu",
+	"Bangladesh Standard Time":        "Asia/Dhaka",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"China Standard Time":             "Asia/Shanghai",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"Taipei Standard Time":            "Asia/Taipei",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"Korea Standard Time":             "Asia/Seoul",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"AUS Central Standard Time":       "Australia/Darwin",
+	"E. Australia Standard Time":      "Australia/Brisbane",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"Tasmania Standard Time":          "Australia/Hobart",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"Pacific Standard Time (Mexico)":  "America/Tijuana",
+	"Mountain Standard Time (Mexico)": "America/Chihuahua",
+}
+
+// loadZone resolves a TZID: an IANA name, a zone named by the calendar's
+// VTIMEZONE (its X-LIC-LOCATION), or a common Windows name.
+func loadZone(tzid string, calendarZones map[string]string) (*time.Location, error) {
+	for _, name := range []string{tzid, calendarZones[tzid], windowsZones[tzid]} {
+		if name == "" || strings.EqualFold(name, "Local") {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownZone, tzid)
+}
+
+// calendarZones maps each VTIMEZONE's TZID to the IANA zone it names in
+// X-LIC-LOCATION, when it names one.
+func calendarZones(lines []string) map[string]string {
+	zones := map[string]string{}
+	depth := 0 // inside a VTIMEZONE; its STANDARD/DAYLIGHT parts are deeper
+	tzid, location := "", ""
+	for _, line := range lines {
+		name, _, value, ok := splitLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VTIMEZONE"):
+			depth, tzid, location = 1, "", ""
+		case depth == 0:
+		case name == "BEGIN":
+			depth++
+		case name == "END":
+			depth--
+			if depth == 0 && tzid != "" && location != "" {
+				zones[tzid] = location
+			}
+		case depth > 1:
+		case name == "TZID":
+			tzid = value
+		case name == "X-LIC-LOCATION":
+			location = value
+		}
+	}
+	return zones
+}
@@ -0,0 +1,305 @@
+// Package index keeps a local SQLite copy of chats and messages so they can
+// be searched when Beeper Desktop is not running.
+package index
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/config"
+)
+
+// FileName is the index database name inside config.DataDir.
+const FileName = "index.db"
+
+const schema = `
+CREATE TABLE IF NOT EXISTS chats (
+	id              TEXT PRIMARY KEY,
+	account_id      TEXT NOT NULL DEFAULT '',
+	network         TEXT NOT NULL DEFAULT '',
+	title           TEXT NOT NULL DEFAULT '',
+	type            TEXT NOT NULL DEFAULT '',
+	last_activity   INTEGER NOT NULL DEFAULT 0,
+	synced_activity INTEGER NOT NULL DEFAULT 0,
+	synced_through  INTEGER NOT NULL DEFAULT 0,
+	catchup_cursor  TEXT NOT NULL DEFAULT '',
+	catchup_newest  INTEGER NOT NULL DEFAULT 0,
+	backfill_cursor TEXT NOT NULL DEFAULT '',
+	backfill_done   INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS participants (
+	chat_id   TEXT NOT NULL,
+	id        TEXT NOT NULL,
+	full_name TEXT NOT NULL DEFAULT '',
+	username  TEXT NOT NULL DEFAULT '',
+	is_self   INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (chat_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS messages (
+	rowid       INTEGER PRIMARY KEY,
+	id          TEXT NOT NULL UNIQUE,
+	chat_id     TEXT NOT NULL,
+	account_id  TEXT NOT NULL DEFAULT '',
+	sender_id   TEXT NOT NULL DEFAULT '',
+	sender      TEXT NOT NULL DEFAULT '',
+	text        TEXT NOT NULL DEFAULT '',
+	timestamp   INTEGER NOT NULL DEFAULT 0,
+	is_me       INTEGER NOT NULL DEFAULT 0,
+	reply_to_id TEXT NOT NULL DEFAULT '',
+	raw         TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS messages_chat_time ON messages (chat_id, timestamp);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+	text, sender,
+	content='messages', content_rowid='rowid',
+	tokenize='unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS messages_ai AFTER INSERT ON messages BEGIN
+	INSERT INTO messages_fts(rowid, text, sender) VALUES (new.rowid, new.text, new.sender);
+END;
+CREATE TRIGGER IF NOT EXISTS messages_ad AFTER DELETE ON messages BEGIN
+	INSERT INTO messages_fts(messages_fts, rowid, text, sender) VALUES ('delete', old.rowid, old.text, old.sender);
+END;
+CREATE TRIGGER IF NOT EXISTS messages_au AFTER UPDATE ON messages BEGIN
+	INSERT INTO messages_fts(messages_fts, rowid, text, sender) VALUES ('delete', old.rowid, old.text, old.sender);
+	INSERT INTO messages_fts(rowid, text, sender) VALUES (new.rowid, new.text, new.sender);
+END;
+`
+
+// Index is a local message database.
+type Index struct {
+	db *sql.DB
+}
+
+// DefaultPath returns the index location under config.DataDir.
+func DefaultPath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Open opens (creating if needed) the index at path.
+func Open(path string) (*Index, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create index dir: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index: %w", err)
+	}
+	// SQLite allows one writer; a single connection avoids lock contention.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize index: %w", err)
+	}
+	return &Index{db: db}, nil
+}
+
+// Close closes the database.
+func (ix *Index) Close() error {
+	return ix.db.Close()
+}
+
+// ChatState records how far a chat has been synced.
+type ChatState struct {
+	// SyncedActivity is the chat's LastActivity when it was last caught up.
+	SyncedActivity time.Time
+	// SyncedThrough is the newest message stored with no gap behind it;
+	// catching up pages back from the newest message until it reaches this.
+	SyncedThrough time.Time
+	// CatchUpCursor resumes a catch-up that ran out of pages, and
+	// CatchUpNewest is the newest message that catch-up has seen.
+	CatchUpCursor string
+	CatchUpNewest time.Time
+	// BackfillCursor resumes paging into older history.
+	BackfillCursor string
+	// BackfillDone is set once the oldest message has been stored.
+	BackfillDone bool
+}
+
+// ChatState returns the sync state of a chat, or a zero state if unknown.
+func (ix *Index) ChatState(ctx context.Context, chatID string) (ChatState, error) {
+	var (
+		state    ChatState
+		activity int64
+		through  int64
+		newest   int64
+		done     int
+	)
+	err := ix.db.QueryRowContext(ctx, `
+		SELECT synced_activity, synced_through, catchup_cursor, catchup_newest, backfill_cursor, backfill_done
+		FROM chats WHERE id = ?`, chatID,
+	).Scan(&activity, &through, &state.CatchUpCursor, &newest, &state.BackfillCursor, &done)
+	if err == sql.ErrNoRows {
+		return ChatState{}, nil
+	}
+	if err != nil {
+		return ChatState{}, err
+	}
+	if activity > 0 {
+		state.SyncedActivity = time.UnixMilli(activity)
+	}
+	if through > 0 {
+		state.SyncedThrough = time.UnixMilli(through)
+	}
+	if newest > 0 {
+		state.CatchUpNewest = time.UnixMilli(newest)
+	}
+	state.BackfillDone = done != 0
+	return state, nil
+}
+
+// SaveChatState stores a chat's sync progress. The chat must already exist.
+func (ix *Index) SaveChatState(ctx context.Context, chatID string, state ChatState) error {
+	_, err := ix.db.ExecContext(ctx, `
+		UPDATE chats SET synced_activity = ?, synced_through = ?, catchup_cursor = ?, catchup_newest = ?,
+			backfill_cursor = ?, backfill_done = ?
+		WHERE id = ?`,
+		unixMilli(state.SyncedActivity), unixMilli(state.SyncedThrough), state.CatchUpCursor,
+		unixMilli(state.CatchUpNewest), state.BackfillCursor, boolInt(state.BackfillDone), chatID)
+	return err
+}
+
+// UpsertChat stores a chat and replaces its participant list.
+func (ix *Index) UpsertChat(ctx context.Context, chat api.Chat) error {
+	tx, err := ix.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO chats (id, account_id, network, title, type, last_activity)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			account_id = excluded.account_id,
+			network = excluded.network,
+			title = excluded.title,
+			type = excluded.type,
+			last_activity = excluded.last_activity`,
+		chat.ID, chat.AccountID, chat.Network, chat.Title, chat.Type, unixMilli(chat.LastActivity))
+	if err != nil {
+		return err
+	}
+
+	if chat.Participants != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM participants WHERE chat_id = ?`, chat.ID); err != nil {
+			return err
+		}
+		for _, p := range chat.Participants.Items {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO participants (chat_id, id, full_name, username, is_self) VALUES (?, ?, ?, ?, ?)`,
+				chat.ID, p.ID, p.FullName, p.Username, boolInt(p.IsSelf))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AddMessages stores messages, skipping any already indexed, and returns how
+// many were new.
+func (ix *Index) AddMessages(ctx context.Context, messages []api.Message) (int, error) {
+	tx, err := ix.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO messages (id, chat_id, account_id, sender_id, sender, text, timestamp, is_me, reply_to_id, raw)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO NOTHING`)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	added := 0
+	for _, m := range messages {
+		raw, err := json.Marshal(m)
+		if err != nil {
+			return 0, err
+		}
+		replyTo := ""
+		if m.ReplyTo != nil {
+			replyTo = m.ReplyTo.ID
+		}
+		res, err := stmt.ExecContext(ctx, m.ID, m.ChatID, m.AccountID, m.SenderID, m.Sender, m.Text,
+			unixMilli(m.Timestamp), boolInt(m.IsMe), replyTo, string(raw))
+		if err != nil {
+			return 0, err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+
+	return added, tx.Commit()
+}
+
+// Stats summarizes the index contents.
+type Stats struct {
+	Chats    int       `json:"chats"`
+	Messages int       `json:"messages"`
+	Complete int       `json:"complete"` // chats whose full history is stored
+	Oldest   time.Time `json:"oldest,omitzero"`
+	Newest   time.Time `json:"newest,omitzero"`
+}
+
+// Stats counts indexed chats and messages.
+func (ix *Index) Stats(ctx context.Context) (Stats, error) {
+	var (
+		st             Stats
+		oldest, newest sql.NullInt64
+	)
+	err := ix.db.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(backfill_done), 0) FROM chats`).Scan(&st.Chats, &st.Complete)
+	if err != nil {
+		return st, err
+	}
+	err = ix.db.QueryRowContext(ctx,
+		`SELECT COUNT(*), MIN(timestamp), MAX(timestamp) FROM messages`).Scan(&st.Messages, &oldest, &newest)
+	if err != nil {
+		return st, err
+	}
+	if oldest.Valid {
+		st.Oldest = time.UnixMilli(oldest.Int64)
+		st.Newest = time.UnixMilli(newest.Int64)
+	}
+	return st, nil
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
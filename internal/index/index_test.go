@@ -0,0 +1,234 @@
+package index
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+func openTestIndex(t *testing.T) *Index {
+	t.Helper()
+	ix, err := Open(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { _ = ix.Close() })
+	return ix
+}
+
+var base = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func seed(t *testing.T, ix *Index) {
+	t.Helper()
+	ctx := context.Background()
+	chats := []api.Chat{
+		{ID: "c1", Title: "Family", Network: "WhatsApp", AccountID: "wa", Participants: &api.ParticipantList{Items: []api.Participant{
+			{ID: "@alice:wa", FullName: "Alice Smith"},
+			{ID: "@me:wa", FullName: "Me", IsSelf: true},
+		}}},
+		{ID: "c2", Title: "Work", Network: "Slack", AccountID: "slack"},
+	}
+	for _, c := range chats {
+		if err := ix.UpsertChat(ctx, c); err != nil {
+			t.Fatalf("UpsertChat() error: %v", err)
+		}
+	}
+	msgs := []api.Message{
+		{ID: "m1", ChatID: "c1", AccountID: "wa", SenderID: "@alice:wa", Text: "Dinner on Sunday at the café?", Timestamp: base},
+		{ID: "m2", ChatID: "c1", AccountID: "wa", SenderID: "@me:wa", Text: "Sunday works, dinner at 7", Timestamp: base.Add(time.Hour), IsMe: true},
+		{ID: "m3", ChatID: "c2", AccountID: "slack", Sender: "Bob", Text: "Deploy dinner-time release", Timestamp: base.Add(24 * time.Hour)},
+		{ID: "m4", ChatID: "c2", AccountID: "slack", Sender: "Bob", Text: "Quarterly planning notes", Timestamp: base.Add(48 * time.Hour)},
+	}
+	added, err := ix.AddMessages(ctx, msgs)
+	if err != nil {
+		t.Fatalf("AddMessages() error: %v", err)
+	}
+	if added != len(msgs) {
+		t.Fatalf("added = %d, want %d", added, len(msgs))
+	}
+}
+
+func TestAddMessagesSkipsDuplicates(t *testing.T) {
+	ix := openTestIndex(t)
+	seed(t, ix)
+
+	added, err := ix.AddMessages(context.Background(), []api.Message{
+		{ID: "m1", ChatID: "c1", Text: "changed"},
+		{ID: "m5", ChatID: "c1", Text: "new", Timestamp: base.Add(72 * time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("AddMessages() error: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("added = %d, want 1", added)
+	}
+
+	st, err := ix.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats() error: %v", err)
+	}
+	if st.Chats != 2 || st.Messages != 5 {
+		t.Errorf("Stats() = %+v, want 2 chats and 5 messages", st)
+	}
+	if !st.Oldest.Equal(base) {
+		t.Errorf("Oldest = %v, want %v", st.Oldest, base)
+	}
+}
+
+func TestChatStateRoundTrip(t *testing.T) {
+	ix := openTestIndex(t)
+	seed(t, ix)
+	ctx := context.Background()
+
+	state, err := ix.ChatState(ctx, "missing")
+	if err != nil || state != (ChatState{}) {
+		t.Fatalf("ChatState(missing) = %+v, %v; want zero state", state, err)
+	}
+
+	want := ChatState{SyncedActivity: base, SyncedThrough: base.Add(-time.Hour), BackfillCursor: "cur", BackfillDone: false}
+	if err := ix.SaveChatState(ctx, "c1", want); err != nil {
+		t.Fatalf("SaveChatState() error: %v", err)
+	}
+	got, err := ix.ChatState(ctx, "c1")
+	if err != nil {
+		t.Fatalf("ChatState() error: %v", err)
+	}
+	if !got.SyncedActivity.Equal(want.SyncedActivity) || !got.SyncedThrough.Equal(want.SyncedThrough) ||
+		got.BackfillCursor != want.BackfillCursor || got.BackfillDone != want.BackfillDone {
+		t.Errorf("ChatState() = %+v, want %+v", got, want)
+	}
+
+	// Re-upserting the chat must keep its sync state.
+	if err := ix.UpsertChat(ctx, api.Chat{ID: "c1", Title: "Family 2"}); err != nil {
+		t.Fatalf("UpsertChat() error: %v", err)
+	}
+	if got, _ := ix.ChatState(ctx, "c1"); got.BackfillCursor != "cur" {
+		t.Errorf("UpsertChat() reset the sync state: %+v", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ix := openTestIndex(t)
+	seed(t, ix)
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all words", Query{Text: "sunday dinner"}, []string{"m1", "m2"}},
+		{"diacritics folded", Query{Text: "cafe"}, []string{"m1"}},
+		{"phrase", Query{Text: `"dinner at 7"`}, []string{"m2"}},
+		{"or", Query{Text: "café OR quarterly"}, []string{"m1", "m4"}},
+		{"negation", Query{Text: "dinner -sunday"}, []string{"m3"}},
+		{"prefix", Query{Text: "plan*"}, []string{"m4"}},
+		{"sender name", Query{Text: "dinner", Sender: "alice"}, []string{"m1"}},
+		{"network", Query{Text: "dinner", Networks: []string{"slack"}}, []string{"m3"}},
+		{"chat", Query{Text: "dinner", ChatIDs: []string{"c1"}}, []string{"m1", "m2"}},
+		{"date range", Query{Text: "dinner", After: base.Add(30 * time.Minute), Before: base.Add(2 * time.Hour)}, []string{"m2"}},
+		{"limit", Query{Text: "dinner", Limit: 1}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := ix.Search(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Search() error: %v", err)
+			}
+			if tt.query.Limit > 0 {
+				if len(results) != tt.query.Limit {
+					t.Errorf("len(results) = %d, want %d", len(results), tt.query.Limit)
+				}
+				return
+			}
+			got := map[string]bool{}
+			for _, r := range results {
+				got[r.ID] = true
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("results = %v, want %v", ids(results), tt.want)
+			}
+			for _, id := range tt.want {
+				if !got[id] {
+					t.Errorf("results = %v, missing %s", ids(results), id)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchResultDetails(t *testing.T) {
+	ix := openTestIndex(t)
+	seed(t, ix)
+
+	results, err := ix.Search(context.Background(), Query{Text: "café"})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Search() = %v, %v; want one result", results, err)
+	}
+	r := results[0]
+	if r.ChatTitle != "Family" || r.Network != "WhatsApp" || r.SenderName != "Alice Smith" {
+		t.Errorf("result = %+v, want chat, network and participant name filled in", r)
+	}
+	if mine, _ := ix.Search(context.Background(), Query{Text: "works"}); len(mine) != 1 || mine[0].SenderName != "You" {
+		t.Errorf("own message sender = %+v, want You", mine)
+	}
+	if !strings.Contains(r.Snippet, MatchStart+"café"+MatchEnd) {
+		t.Errorf("Snippet = %q, want highlighted match", r.Snippet)
+	}
+	if !r.Timestamp.Equal(base) {
+		t.Errorf("Timestamp = %v, want %v", r.Timestamp, base)
+	}
+}
+
+func TestFTSQuery(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "hello world", want: `"hello" "world"`},
+		{in: `"exact phrase" other`, want: `"exact phrase" "other"`},
+		{in: "a OR b", want: `"a" OR "b"`},
+		{in: "a NOT b", want: `"a" NOT "b"`},
+		{in: "a -b", want: `"a" NOT "b"`},
+		{in: `a -"b c"`, want: `"a" NOT "b c"`},
+		{in: "(a OR b) c", want: `( "a" OR "b" ) "c"`},
+		{in: "pre*", want: `"pre"*`},
+		{in: "user@example.com", want: `"user@example.com"`},
+		{in: "and or", want: `"and" "or"`},
+		{in: "", wantErr: true},
+		{in: "-a", wantErr: true},
+		{in: "OR a", wantErr: true},
+		{in: "a OR", wantErr: true},
+		{in: `"open`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ftsQuery(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ftsQuery(%q) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ftsQuery(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ftsQuery(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func ids(results []Result) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.ID
+	}
+	return out
+}
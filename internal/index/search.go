@@ -0,0 +1,235 @@
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+// Query describes an offline search.
+type Query struct {
+	// Text uses a small search syntax: bare words must all match, "quoted
+	// phrases" match exactly, OR and NOT (or a leading -) combine terms,
+	// parentheses group, and a trailing * matches a prefix.
+	Text     string
+	After    time.Time
+	Before   time.Time
+	Sender   string   // substring of the sender's name, case-insensitive
+	Networks []string // network names or account IDs
+	ChatIDs  []string
+	Limit    int // 0 means no limit
+}
+
+// Result is a matching message with its chat and ranking.
+type Result struct {
+	api.Message
+	ChatTitle  string  `json:"chatTitle,omitempty"`
+	Network    string  `json:"network,omitempty"`
+	SenderName string  `json:"senderName,omitempty"`
+	Snippet    string  `json:"snippet"`
+	Rank       float64 `json:"rank"`
+}
+
+// Snippet markers wrap matched terms in Result.Snippet.
+const (
+	MatchStart = "\x02"
+	MatchEnd   = "\x03"
+)
+
+// Search runs a full-text query, best matches first.
+func (ix *Index) Search(ctx context.Context, q Query) ([]Result, error) {
+	match, err := ftsQuery(q.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		where = []string{"messages_fts MATCH ?"}
+		args  = []any{match}
+	)
+	if !q.After.IsZero() {
+		where = append(where, "m.timestamp >= ?")
+		args = append(args, q.After.UnixMilli())
+	}
+	if !q.Before.IsZero() {
+		where = append(where, "m.timestamp < ?")
+		args = append(args, q.Before.UnixMilli())
+	}
+	if q.Sender != "" {
+		where = append(where, "(COALESCE(p.full_name, '') LIKE ? OR COALESCE(p.username, '') LIKE ? OR m.sender LIKE ?)")
+		like := "%" + q.Sender + "%"
+		args = append(args, like, like, like)
+	}
+	if len(q.Networks) > 0 {
+		var ors []string
+		for _, n := range q.Networks {
+			ors = append(ors, "c.network LIKE ? OR m.account_id LIKE ?")
+			args = append(args, n, n)
+		}
+		where = append(where, "("+strings.Join(ors, " OR ")+")")
+	}
+	if len(q.ChatIDs) > 0 {
+		where = append(where, "m.chat_id IN ("+placeholders(len(q.ChatIDs))+")")
+		for _, id := range q.ChatIDs {
+			args = append(args, id)
+		}
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = -1 // no limit
+	}
+	args = append(args, limit)
+
+	rows, err := ix.db.QueryContext(ctx, `
+		SELECT m.raw, COALESCE(c.title, ''), COALESCE(c.network, ''), COALESCE(c.type, ''),
+			COALESCE(p.id, ''), COALESCE(p.full_name, ''), COALESCE(p.username, ''), COALESCE(p.is_self, 0),
+			snippet(messages_fts, 0, '`+MatchStart+`', '`+MatchEnd+`', '…', 12),
+			bm25(messages_fts)
+		FROM messages_fts
+		JOIN messages m ON m.rowid = messages_fts.rowid
+		LEFT JOIN chats c ON c.id = m.chat_id
+		LEFT JOIN participants p ON p.chat_id = m.chat_id AND p.id = m.sender_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY bm25(messages_fts), m.timestamp DESC
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []Result
+	for rows.Next() {
+		var (
+			r        Result
+			raw      string
+			chatType string
+			p        api.Participant
+		)
+		if err := rows.Scan(&raw, &r.ChatTitle, &r.Network, &chatType, &p.ID, &p.FullName, &p.Username, &p.IsSelf, &r.Snippet, &r.Rank); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(raw), &r.Message); err != nil {
+			return nil, fmt.Errorf("corrupt message in index: %w", err)
+		}
+		// Name the sender the same way live output does, from the one
+		// participant row that matched.
+		chat := &api.Chat{Title: r.ChatTitle, Type: chatType}
+		if p.ID != "" {
+			chat.Participants = &api.ParticipantList{Items: []api.Participant{p}}
+		}
+		r.SenderName = api.SenderName(r.Message, chat)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// ftsQuery translates the user-facing search syntax into an FTS5 MATCH
+// expression. Every word is quoted so punctuation can't break the query.
+func ftsQuery(input string) (string, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("empty search query")
+	}
+
+	var out []string
+	expectTerm := true // at the start, or after an operator / "("
+	for i, tok := range tokens {
+		switch tok {
+		case "AND", "OR", "NOT":
+			if expectTerm {
+				return "", fmt.Errorf("%s must follow a search term", tok)
+			}
+			if i == len(tokens)-1 {
+				return "", fmt.Errorf("%s must be followed by a search term", tok)
+			}
+			out = append(out, tok)
+			expectTerm = true
+		case "(":
+			out = append(out, tok)
+			expectTerm = true
+		case ")":
+			out = append(out, tok)
+			expectTerm = false
+		default:
+			if strings.HasPrefix(tok, "-") && len(tok) > 1 {
+				if expectTerm {
+					return "", fmt.Errorf("a negated term (%s) must follow another search term", tok)
+				}
+				out = append(out, "NOT", quoteTerm(tok[1:]))
+			} else {
+				out = append(out, quoteTerm(tok))
+			}
+			expectTerm = false
+		}
+	}
+	return strings.Join(out, " "), nil
+}
+
+// tokenize splits a query into words, quoted phrases, operators and parens.
+func tokenize(input string) ([]string, error) {
+	var (
+		tokens []string
+		runes  = []rune(input)
+	)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '"' || (r == '-' && i+1 < len(runes) && runes[i+1] == '"'):
+			neg := r == '-'
+			if neg {
+				i++
+			}
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated quote in search query")
+			}
+			phrase := "\"" + string(runes[i+1:end]) + "\""
+			if neg {
+				phrase = "-" + phrase
+			}
+			tokens = append(tokens, phrase)
+			i = end + 1
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+	return tokens, nil
+}
+
+// quoteTerm quotes a word or phrase for FTS5, keeping a trailing * as a
+// prefix match.
+func quoteTerm(term string) string {
+	prefix := strings.HasSuffix(term, "*") && !strings.HasPrefix(term, "\"")
+	term = strings.TrimSuffix(term, "*")
+	term = strings.Trim(term, "\"")
+	quoted := "\"" + strings.ReplaceAll(term, "\"", "\"\"") + "\""
+	if prefix {
+		quoted += "*"
+	}
+	return quoted
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
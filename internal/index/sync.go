@@ -0,0 +1,201 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+// SyncOptions controls a sync run.
+type SyncOptions struct {
+	// ChatIDs limits the sync to these chats; empty means every chat.
+	ChatIDs []string
+	// AccountIDs limits the chat scan to these accounts.
+	AccountIDs []string
+	// MaxPages caps history pages fetched per chat in this run (0 = no
+	// limit). Interrupted backfills resume on the next run.
+	MaxPages int
+}
+
+// ChatSync reports what a sync did for one chat.
+type ChatSync struct {
+	ChatID   string `json:"chatID"`
+	Title    string `json:"title"`
+	Added    int    `json:"added"`
+	Skipped  bool   `json:"skipped,omitempty"` // nothing new since the last sync
+	Complete bool   `json:"complete"`          // full history stored
+	Error    string `json:"error,omitempty"`
+}
+
+// SyncReport summarizes a sync run.
+type SyncReport struct {
+	Scan  *api.ChatScan `json:"scan,omitempty"`
+	Chats []ChatSync    `json:"chats"`
+	Added int           `json:"added"`
+}
+
+// Sync copies chats and messages from the API into the index. Each chat is
+// caught up from its newest message until a page overlaps what is already
+// stored, then older history is backfilled from a saved cursor, so repeated
+// runs only fetch what is new. progress, if non-nil, is called per chat.
+func Sync(ctx context.Context, client *api.Client, ix *Index, opts SyncOptions, progress func(ChatSync)) (*SyncReport, error) {
+	report := &SyncReport{}
+
+	var chats []api.Chat
+	if len(opts.ChatIDs) > 0 {
+		for _, id := range opts.ChatIDs {
+			chat, err := client.Chats().Get(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			chats = append(chats, *chat)
+		}
+	} else {
+		scan, err := client.Chats().Enumerate(ctx, api.EnumerateParams{AccountIDs: opts.AccountIDs})
+		if err != nil {
+			return nil, err
+		}
+		report.Scan = scan
+		chats = scan.Chats
+	}
+
+	for _, chat := range chats {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		cs := syncChat(ctx, client, ix, chat, opts.MaxPages)
+		report.Chats = append(report.Chats, cs)
+		report.Added += cs.Added
+		if progress != nil {
+			progress(cs)
+		}
+	}
+	return report, nil
+}
+
+func syncChat(ctx context.Context, client *api.Client, ix *Index, chat api.Chat, maxPages int) ChatSync {
+	cs := ChatSync{ChatID: chat.ID, Title: chat.Title}
+	fail := func(err error) ChatSync {
+		cs.Error = err.Error()
+		return cs
+	}
+
+	state, err := ix.ChatState(ctx, chat.ID)
+	if err != nil {
+		return fail(err)
+	}
+	if err := ix.UpsertChat(ctx, chat); err != nil {
+		return fail(err)
+	}
+
+	fresh := state.SyncedThrough.IsZero() && state.BackfillCursor == "" && !state.BackfillDone
+	upToDate := !state.SyncedActivity.IsZero() && !chat.LastActivity.After(state.SyncedActivity)
+	if upToDate && state.BackfillDone && state.CatchUpCursor == "" {
+		cs.Skipped = true
+		cs.Complete = true
+		return cs
+	}
+
+	pages := 0
+	fetch := func(cursor string) (*api.ListMessagesResponse, error) {
+		page, err := client.Messages().List(ctx, chat.ID, api.ListMessagesParams{Cursor: cursor, Direction: "before"})
+		if err != nil {
+			return nil, err
+		}
+		pages++
+		added, err := ix.AddMessages(ctx, page.Items)
+		if err != nil {
+			return nil, err
+		}
+		cs.Added += added
+		return page, nil
+	}
+	budget := func() bool { return maxPages <= 0 || pages < maxPages }
+	save := func() error {
+		if err := ix.SaveChatState(ctx, chat.ID, state); err != nil {
+			return fmt.Errorf("failed to save sync state: %w", err)
+		}
+		return nil
+	}
+
+	if !fresh && (!upToDate || state.CatchUpCursor != "") {
+		// Catch up from the newest message back to SyncedThrough. A walk that
+		// runs out of pages saves its cursor and resumes on the next run, so
+		// the gap below it is never recorded as synced.
+		resumed := state.CatchUpCursor != ""
+		reached := false
+		for !reached && budget() {
+			page, err := fetch(state.CatchUpCursor)
+			if err != nil {
+				return fail(err)
+			}
+			for _, m := range page.Items {
+				if m.Timestamp.After(state.CatchUpNewest) {
+					state.CatchUpNewest = m.Timestamp
+				}
+				if !m.Timestamp.After(state.SyncedThrough) {
+					reached = true
+				}
+			}
+			if !page.HasMore || page.Cursor == "" {
+				reached = true
+			} else if page.Cursor == state.CatchUpCursor {
+				// The API repeated the cursor. Stop, keeping the gap below it
+				// unsynced so a later run tries again.
+				if err := save(); err != nil {
+					return fail(err)
+				}
+				return fail(fmt.Errorf("message history repeated cursor %q while catching up", page.Cursor))
+			}
+			state.CatchUpCursor = page.Cursor
+		}
+		if reached {
+			if state.CatchUpNewest.After(state.SyncedThrough) {
+				state.SyncedThrough = state.CatchUpNewest
+			}
+			// A resumed walk started before newer activity may have arrived;
+			// only claim what it actually saw so the next run catches up again.
+			state.SyncedActivity = chat.LastActivity
+			if resumed {
+				state.SyncedActivity = state.SyncedThrough
+			}
+			state.CatchUpCursor = ""
+			state.CatchUpNewest = time.Time{}
+		}
+		if err := save(); err != nil {
+			return fail(err)
+		}
+	}
+
+	// Backfill older history. A fresh chat starts from the newest message,
+	// which also establishes SyncedThrough.
+	for (fresh || (!state.BackfillDone && state.BackfillCursor != "")) && budget() {
+		page, err := fetch(state.BackfillCursor)
+		if err != nil {
+			return fail(err)
+		}
+		if fresh {
+			for _, m := range page.Items {
+				if m.Timestamp.After(state.SyncedThrough) {
+					state.SyncedThrough = m.Timestamp
+				}
+			}
+			state.SyncedActivity = chat.LastActivity
+			fresh = false
+		}
+		if !page.HasMore || page.Cursor == "" || page.Cursor == state.BackfillCursor {
+			state.BackfillDone = true
+			state.BackfillCursor = ""
+		} else {
+			state.BackfillCursor = page.Cursor
+		}
+		if err := save(); err != nil {
+			return fail(err)
+		}
+	}
+
+	cs.Complete = state.BackfillDone
+	return cs
+}
@@ -0,0 +1,203 @@
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+// fakeChat serves one chat whose history is paged two messages at a time,
+// newest first, with the page offset as the cursor.
+type fakeChat struct {
+	messages []api.Message // newest first
+	pages    int
+	// stuck, when set, is returned as the cursor of every page.
+	stuck string
+}
+
+func (f *fakeChat) add(n int) {
+	for range n {
+		i := len(f.messages)
+		m := api.Message{ID: fmt.Sprintf("m%d", i), ChatID: "c1", Text: fmt.Sprintf("message %d", i), Timestamp: base.Add(time.Duration(i) * time.Minute)}
+		f.messages = append([]api.Message{m}, f.messages...)
+	}
+}
+
+func (f *fakeChat) server(t *testing.T) *api.Client {
+	t.Helper()
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chats/c1":
+			chat := api.Chat{ID: "c1", Title: "Chat"}
+			if len(f.messages) > 0 {
+				chat.LastActivity = f.messages[0].Timestamp
+			}
+			data, _ := json.Marshal(chat)
+			testutil.JSONResponse(w, http.StatusOK, string(data))
+		case "/v1/chats/c1/messages":
+			f.pages++
+			offset, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+			end := min(offset+2, len(f.messages))
+			page := api.ListMessagesResponse{Items: f.messages[offset:end], HasMore: end < len(f.messages)}
+			if page.HasMore {
+				page.Cursor = strconv.Itoa(end)
+			}
+			if f.stuck != "" {
+				page.HasMore, page.Cursor = true, f.stuck
+			}
+			data, _ := json.Marshal(page)
+			testutil.JSONResponse(w, http.StatusOK, string(data))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+	return api.NewClient(server.URL, "test-token")
+}
+
+func messageCount(t *testing.T, ix *Index) int {
+	t.Helper()
+	st, err := ix.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats() error: %v", err)
+	}
+	return st.Messages
+}
+
+func TestSyncResumesBackfill(t *testing.T) {
+	f := &fakeChat{}
+	f.add(7)
+	client := f.server(t)
+	ix := openTestIndex(t)
+	opts := SyncOptions{ChatIDs: []string{"c1"}, MaxPages: 2}
+
+	for run := 1; run <= 2; run++ {
+		report, err := Sync(context.Background(), client, ix, opts, nil)
+		if err != nil {
+			t.Fatalf("Sync() error: %v", err)
+		}
+		if cs := report.Chats[0]; cs.Error != "" || cs.Complete != (run == 2) {
+			t.Fatalf("run %d: %+v", run, cs)
+		}
+	}
+	if n := messageCount(t, ix); n != 7 {
+		t.Errorf("indexed %d messages, want 7", n)
+	}
+	if f.pages != 4 {
+		t.Errorf("fetched %d pages, want 4", f.pages)
+	}
+}
+
+func TestSyncCatchesUpIncrementally(t *testing.T) {
+	f := &fakeChat{}
+	f.add(5)
+	client := f.server(t)
+	ix := openTestIndex(t)
+	opts := SyncOptions{ChatIDs: []string{"c1"}}
+
+	if _, err := Sync(context.Background(), client, ix, opts, nil); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+
+	// Nothing new: the chat is skipped without listing messages.
+	f.pages = 0
+	report, err := Sync(context.Background(), client, ix, opts, nil)
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if !report.Chats[0].Skipped || f.pages != 0 {
+		t.Errorf("unchanged chat: %+v after %d pages, want skipped", report.Chats[0], f.pages)
+	}
+
+	// Three new messages: two pages reach the previously synced message.
+	f.add(3)
+	f.pages = 0
+	report, err = Sync(context.Background(), client, ix, opts, nil)
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if cs := report.Chats[0]; cs.Added != 3 || !cs.Complete {
+		t.Errorf("catch-up: %+v, want 3 added", cs)
+	}
+	if f.pages != 2 {
+		t.Errorf("fetched %d pages, want 2", f.pages)
+	}
+	if n := messageCount(t, ix); n != 8 {
+		t.Errorf("indexed %d messages, want 8", n)
+	}
+}
+
+func TestSyncInterruptedCatchUpLeavesNoGap(t *testing.T) {
+	f := &fakeChat{}
+	f.add(2)
+	client := f.server(t)
+	ix := openTestIndex(t)
+
+	if _, err := Sync(context.Background(), client, ix, SyncOptions{ChatIDs: []string{"c1"}}, nil); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+
+	// Five new messages, but only one page allowed: the run stops short.
+	f.add(5)
+	opts := SyncOptions{ChatIDs: []string{"c1"}, MaxPages: 1}
+	if _, err := Sync(context.Background(), client, ix, opts, nil); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+
+	// Later runs must keep walking back to the old messages rather than
+	// stopping at the page the interrupted run stored.
+	for range 3 {
+		if _, err := Sync(context.Background(), client, ix, opts, nil); err != nil {
+			t.Fatalf("Sync() error: %v", err)
+		}
+	}
+	if n := messageCount(t, ix); n != 7 {
+		t.Errorf("indexed %d messages, want 7", n)
+	}
+}
+
+func TestSyncCatchUpStopsOnRepeatedCursor(t *testing.T) {
+	f := &fakeChat{}
+	f.add(2)
+	client := f.server(t)
+	ix := openTestIndex(t)
+	opts := SyncOptions{ChatIDs: []string{"c1"}} // no page limit
+
+	if _, err := Sync(context.Background(), client, ix, opts, nil); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	before, _ := ix.ChatState(context.Background(), "c1")
+
+	// Every page points back at the same cursor, and all its messages are new.
+	f.add(6)
+	f.stuck = "2"
+	f.pages = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report, err := Sync(ctx, client, ix, opts, nil)
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if cs := report.Chats[0]; cs.Error == "" || f.pages > 2 {
+		t.Fatalf("repeated cursor: %+v after %d pages, want an error after 2", cs, f.pages)
+	}
+	after, _ := ix.ChatState(context.Background(), "c1")
+	if !after.SyncedThrough.Equal(before.SyncedThrough) {
+		t.Errorf("SyncedThrough moved from %v to %v across an unsynced gap", before.SyncedThrough, after.SyncedThrough)
+	}
+
+	// Once the API behaves, the next run fills the gap.
+	f.stuck = ""
+	if _, err := Sync(context.Background(), client, ix, opts, nil); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if n := messageCount(t, ix); n != 8 {
+		t.Errorf("indexed %d messages, want 8", n)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"strings"
 	"text/tabwriter"
+	"unicode/utf8"
 
 	"github.com/itchyny/gojq"
 )
@@ -107,3 +109,55 @@ func OutputWithQuery(format, query string, data any, textFn func(io.Writer)) err
 		return nil
 	}
 }
+
+// StreamTable prints rows as soon as they are appended, padding cells to
+// fixed widths instead of measuring every row first. Use it for listings
+// that arrive page by page.
+type StreamTable struct {
+	w      io.Writer
+	widths []int
+}
+
+func NewStreamTable(w io.Writer, widths ...int) *StreamTable {
+	return &StreamTable{w: w, widths: widths}
+}
+
+// Row writes one line. Cells beyond the configured widths are written as-is.
+func (t *StreamTable) Row(cells ...string) {
+	for i, cell := range cells {
+		if i > 0 {
+			_, _ = io.WriteString(t.w, "  ")
+		}
+		if i < len(t.widths) && i < len(cells)-1 {
+			cell = padRight(cell, t.widths[i])
+		}
+		_, _ = io.WriteString(t.w, cell)
+	}
+	_, _ = io.WriteString(t.w, "\n")
+}
+
+func padRight(s string, width int) string {
+	n := utf8.RuneCountInString(stripANSI(s))
+	if n >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// stripANSI removes color escape sequences so they don't count toward widths.
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\033[") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
+			for i < len(s) && s[i] != 'm' {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -36,3 +36,15 @@ func TestJSONOutput(t *testing.T) {
 		t.Errorf("WriteJSON() = %q, want %q", got, expected)
 	}
 }
+
+func TestStreamTablePadsColumns(t *testing.T) {
+	var buf bytes.Buffer
+	st := NewStreamTable(&buf, 5, 3)
+	st.Row("ID", "Net", "Name")
+	st.Row("abc", Colorize("x", Bold, true), "Alice")
+
+	want := "ID     Net  Name\nabc    " + Colorize("x", Bold, true) + "    Alice\n"
+	if buf.String() != want {
+		t.Errorf("StreamTable output = %q, want %q", buf.String(), want)
+	}
+}
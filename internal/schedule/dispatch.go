@@ -0,0 +1,96 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+// Retry policy for failed sends.
+const (
+	MaxAttempts = 5
+	retryBase   = time.Minute
+	// sendingStale is how long an item may stay in StatusSending before it
+	// is treated as interrupted.
+	sendingStale = 10 * time.Minute
+)
+
+// Sender delivers a message; *api.MessagesService implements it.
+type Sender interface {
+	Send(ctx context.Context, chatID string, req api.SendMessageRequest) (*api.SendMessageResponse, error)
+}
+
+// Result is the outcome of one delivery attempt.
+type Result struct {
+	Item Item
+	Err  error
+}
+
+// Dispatch sends every item due at now. Items are claimed under the queue
+// lock before sending, so two dispatchers never send the same item. Failed
+// items stay in the queue and are retried with backoff, up to MaxAttempts.
+// Items left sending by a dispatcher that died are marked interrupted and
+// not sent again until retried.
+func Dispatch(ctx context.Context, q *Queue, sender Sender, now time.Time) ([]Result, error) {
+	var due []Item
+	err := q.Update(func(items []Item) ([]Item, error) {
+		for i := range items {
+			it := &items[i]
+			if it.Status == StatusSending && now.Sub(it.NextAttempt) > sendingStale {
+				// A dispatcher died mid-send; the message may or may not
+				// have gone out, so it waits for an explicit retry.
+				it.Status = StatusInterrupted
+				it.LastError = "interrupted while sending; check the chat before retrying"
+				it.NextAttempt = time.Time{}
+				continue
+			}
+			if it.Due(now) {
+				it.Status = StatusSending
+				it.Attempts++
+				it.NextAttempt = now
+				due = append(due, *it)
+			}
+		}
+		return items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(due))
+	for _, it := range due {
+		resp, sendErr := sender.Send(ctx, it.ChatID, api.SendMessageRequest{Text: it.Text, ReplyToMessageID: it.ReplyTo})
+		err := q.Update(func(items []Item) ([]Item, error) {
+			for i := range items {
+				if items[i].ID != it.ID {
+					continue
+				}
+				if sendErr != nil {
+					items[i].Status = StatusFailed
+					items[i].LastError = sendErr.Error()
+					items[i].NextAttempt = now.Add(backoff(items[i].Attempts))
+				} else {
+					items[i].Status = StatusSent
+					items[i].SentAt = time.Now()
+					items[i].MessageID = resp.MessageID
+					items[i].LastError = ""
+					items[i].NextAttempt = time.Time{}
+				}
+				it = items[i]
+			}
+			return items, nil
+		})
+		if err != nil {
+			return results, fmt.Errorf("failed to record delivery of %s: %w", it.ID, err)
+		}
+		results = append(results, Result{Item: it, Err: sendErr})
+	}
+	return results, nil
+}
+
+// backoff is the wait before retrying after attempts failures: 1m, 2m, 4m...
+func backoff(attempts int) time.Duration {
+	return retryBase << max(attempts-1, 0)
+}
@@ -0,0 +1,268 @@
+// Package schedule keeps a durable queue of messages to send later and
+// delivers them when they fall due.
+package schedule
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/config"
+)
+
+// FileName is the queue file inside config.DataDir.
+const FileName = "schedule.json"
+
+// Item statuses.
+const (
+	StatusPending = "pending"
+	StatusSending = "sending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	// StatusInterrupted is a send that never finished. The message may have
+	// gone out, so it is only sent again through Retry.
+	StatusInterrupted = "interrupted"
+)
+
+// Item is one scheduled message.
+type Item struct {
+	ID        string    `json:"id"`
+	ChatID    string    `json:"chatID"`
+	ChatTitle string    `json:"chatTitle,omitempty"`
+	Text      string    `json:"text"`
+	ReplyTo   string    `json:"replyTo,omitempty"`
+	SendAt    time.Time `json:"sendAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	Status    string    `json:"status"`
+
+	Attempts    int       `json:"attempts,omitempty"`
+	NextAttempt time.Time `json:"nextAttempt,omitzero"`
+	LastError   string    `json:"lastError,omitempty"`
+	SentAt      time.Time `json:"sentAt,omitzero"`
+	MessageID   string    `json:"messageID,omitempty"`
+}
+
+// Due reports whether the item should be sent at now.
+func (it *Item) Due(now time.Time) bool {
+	switch it.Status {
+	case StatusPending:
+		return !it.SendAt.After(now)
+	case StatusFailed:
+		return it.Attempts < MaxAttempts && !it.NextAttempt.After(now)
+	}
+	return false
+}
+
+// ErrNotFound is returned for unknown item IDs.
+var ErrNotFound = errors.New("scheduled message not found")
+
+// Queue is the queue file. Every change goes through Update, which holds a
+// lock file so a running dispatcher and other commands don't overwrite each
+// other.
+type Queue struct {
+	path string
+}
+
+type queueFile struct {
+	Items []Item `json:"items"`
+}
+
+// DefaultPath returns the queue location under config.DataDir.
+func DefaultPath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Open returns the queue stored at path.
+func Open(path string) *Queue {
+	return &Queue{path: path}
+}
+
+// Path returns the queue file location.
+func (q *Queue) Path() string {
+	return q.path
+}
+
+// Items returns every item, ordered by send time.
+func (q *Queue) Items() ([]Item, error) {
+	items, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+	sortItems(items)
+	return items, nil
+}
+
+// Add stores a new pending item and returns it with its ID set.
+func (q *Queue) Add(it Item) (Item, error) {
+	it.ID = newID()
+	it.Status = StatusPending
+	if it.CreatedAt.IsZero() {
+		it.CreatedAt = time.Now()
+	}
+	err := q.Update(func(items []Item) ([]Item, error) {
+		return append(items, it), nil
+	})
+	return it, err
+}
+
+// Cancel removes a pending or failed item.
+func (q *Queue) Cancel(id string) (Item, error) {
+	var removed Item
+	err := q.Update(func(items []Item) ([]Item, error) {
+		i := slices.IndexFunc(items, func(it Item) bool { return it.ID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		switch items[i].Status {
+		case StatusSent:
+			return nil, fmt.Errorf("message %s was already sent", id)
+		case StatusSending:
+			return nil, fmt.Errorf("message %s is being sent right now", id)
+		}
+		removed = items[i]
+		return slices.Delete(items, i, i+1), nil
+	})
+	return removed, err
+}
+
+// Retry makes a failed or interrupted item due again immediately, resetting
+// its attempts.
+func (q *Queue) Retry(id string) error {
+	return q.Update(func(items []Item) ([]Item, error) {
+		i := slices.IndexFunc(items, func(it Item) bool { return it.ID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		if items[i].Status != StatusFailed && items[i].Status != StatusInterrupted {
+			return nil, fmt.Errorf("message %s is %s, not failed", id, items[i].Status)
+		}
+		items[i].Status = StatusFailed
+		items[i].Attempts = 0
+		items[i].NextAttempt = time.Time{}
+		return items, nil
+	})
+}
+
+// Prune drops sent items delivered before cutoff.
+func (q *Queue) Prune(cutoff time.Time) (int, error) {
+	removed := 0
+	err := q.Update(func(items []Item) ([]Item, error) {
+		before := len(items)
+		items = slices.DeleteFunc(items, func(it Item) bool {
+			return it.Status == StatusSent && it.SentAt.Before(cutoff)
+		})
+		removed = before - len(items)
+		return items, nil
+	})
+	return removed, err
+}
+
+// Update loads the queue under the lock, applies fn, and saves the result.
+// Nothing is written if fn returns an error.
+func (q *Queue) Update(fn func([]Item) ([]Item, error)) error {
+	unlock, err := lockFile(q.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	items, err := q.load()
+	if err != nil {
+		return err
+	}
+	items, err = fn(items)
+	if err != nil {
+		return err
+	}
+	return q.save(items)
+}
+
+func (q *Queue) load() ([]Item, error) {
+	data, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule: %w", err)
+	}
+	var f queueFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule %s: %w", q.path, err)
+	}
+	return f.Items, nil
+}
+
+func (q *Queue) save(items []Item) error {
+	sortItems(items)
+	data, err := json.MarshalIndent(queueFile{Items: items}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(q.path, data); err != nil {
+		return fmt.Errorf("failed to write schedule: %w", err)
+	}
+	return nil
+}
+
+// Lock timing: how long to wait for another process, and when a leftover
+// lock from a crashed process is considered stale.
+const (
+	lockWait  = 10 * time.Second
+	lockStale = time.Minute
+)
+
+// lockFile takes an exclusive lock file next to path.
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create schedule dir: %w", err)
+	}
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %s: %w", filepath.Base(path), err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another process (remove %s if none is running)", filepath.Base(path), lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// writeFileAtomic replaces path with data through a temporary file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func sortItems(items []Item) {
+	slices.SortStableFunc(items, func(a, b Item) int { return a.SendAt.Compare(b.SendAt) })
+}
+
+func newID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
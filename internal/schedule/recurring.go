@@ -0,0 +1,312 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/config"
+	"github.com/salmonumbrella/beeper-cli/internal/timeexpr"
+)
+
+// RecurringFileName is the recurring reminders file inside config.DataDir.
+const RecurringFileName = "reminders.json"
+
+// Recurrence is a time of day on a set of weekdays, in a time zone.
+type Recurrence struct {
+	Days   []time.Weekday `json:"days"`
+	Hour   int            `json:"hour"`
+	Minute int            `json:"minute"`
+	Zone   string         `json:"zone,omitempty"` // IANA name; empty is local time
+}
+
+var (
+	weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	weekend  = []time.Weekday{time.Saturday, time.Sunday}
+	everyDay = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+)
+
+// ParseEvery reads a recurrence such as "weekday 9:00", "daily 8am",
+// "weekend" or "mon,wed,fri 17:30". The time of day is optional when at is
+// given, and defaults to timeexpr.DefaultHour otherwise.
+func ParseEvery(spec, at, zone string) (Recurrence, error) {
+	r := Recurrence{Hour: timeexpr.DefaultHour, Zone: zone}
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 {
+		return r, fmt.Errorf("empty recurrence")
+	}
+	if len(fields) > 1 {
+		at = strings.Join(fields[1:], " ")
+	}
+	if at != "" {
+		h, m, ok := timeexpr.ParseClock(at)
+		if !ok {
+			return r, fmt.Errorf("invalid time of day %q, e.g. 9:00 or 5pm", at)
+		}
+		r.Hour, r.Minute = h, m
+	}
+
+	switch fields[0] {
+	case "day", "days", "daily":
+		r.Days = slices.Clone(everyDay)
+	case "weekday", "weekdays":
+		r.Days = slices.Clone(weekdays)
+	case "weekend", "weekends":
+		r.Days = slices.Clone(weekend)
+	default:
+		for name := range strings.SplitSeq(fields[0], ",") {
+			d, ok := timeexpr.Weekday(strings.TrimSuffix(name, "s"))
+			if !ok {
+				return r, fmt.Errorf("unknown day %q. Use daily, weekday, weekend or day names like mon,wed,fri", name)
+			}
+			if !slices.Contains(r.Days, d) {
+				r.Days = append(r.Days, d)
+			}
+		}
+	}
+	if _, err := r.location(); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// Next returns the first occurrence strictly after t.
+func (r Recurrence) Next(t time.Time) time.Time {
+	loc, err := r.location()
+	if err != nil {
+		loc = time.Local
+	}
+	local := t.In(loc)
+	for i := 0; i <= 7; i++ {
+		day := local.AddDate(0, 0, i)
+		at := time.Date(day.Year(), day.Month(), day.Day(), r.Hour, r.Minute, 0, 0, loc)
+		if slices.Contains(r.Days, at.Weekday()) && at.After(t) {
+			return at
+		}
+	}
+	return time.Time{}
+}
+
+// String describes r, e.g. "weekdays at 09:00".
+func (r Recurrence) String() string {
+	var days string
+	switch {
+	case sameDays(r.Days, everyDay):
+		days = "daily"
+	case sameDays(r.Days, weekdays):
+		days = "weekdays"
+	case sameDays(r.Days, weekend):
+		days = "weekends"
+	default:
+		names := make([]string, len(r.Days))
+		for i, d := range r.Days {
+			names[i] = d.String()[:3]
+		}
+		days = strings.Join(names, ",")
+	}
+	s := fmt.Sprintf("%s at %02d:%02d", days, r.Hour, r.Minute)
+	if r.Zone != "" {
+		s += " " + r.Zone
+	}
+	return s
+}
+
+func (r Recurrence) location() (*time.Location, error) {
+	if r.Zone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(r.Zone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone: %w", err)
+	}
+	return loc, nil
+}
+
+func sameDays(a, b []time.Weekday) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, d := range a {
+		if !slices.Contains(b, d) {
+			return false
+		}
+	}
+	return true
+}
+
+// Recurring is a chat reminder that is re-armed after each occurrence. The
+// API holds one reminder per chat, so only Next is ever set there.
+type Recurring struct {
+	ID        string     `json:"id"`
+	ChatID    string     `json:"chatID"`
+	ChatTitle string     `json:"chatTitle,omitempty"`
+	Every     Recurrence `json:"every"`
+	Next      time.Time  `json:"next,omitzero"` // the occurrence set in Beeper
+	CreatedAt time.Time  `json:"createdAt"`
+	LastError string     `json:"lastError,omitempty"`
+}
+
+// Due reports whether r's armed occurrence has passed, or it was never armed.
+func (r *Recurring) Due(now time.Time) bool {
+	return r.Next.IsZero() || !r.Next.After(now)
+}
+
+// RecurringStore is the recurring reminders file, locked like the queue.
+type RecurringStore struct {
+	path string
+}
+
+type recurringFile struct {
+	Reminders []Recurring `json:"reminders"`
+}
+
+// DefaultRecurringPath returns the recurring reminders file under
+// config.DataDir.
+func DefaultRecurringPath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, RecurringFileName), nil
+}
+
+// OpenRecurring returns the store at path.
+func OpenRecurring(path string) *RecurringStore {
+	return &RecurringStore{path: path}
+}
+
+// Path returns the file location.
+func (s *RecurringStore) Path() string {
+	return s.path
+}
+
+// Items returns every recurring reminder, by next occurrence.
+func (s *RecurringStore) Items() ([]Recurring, error) {
+	return s.load()
+}
+
+// Add stores r with a new ID and returns it. Any other recurring reminder on
+// the same chat is replaced, since the chat can only hold one.
+func (s *RecurringStore) Add(r Recurring) (Recurring, error) {
+	r.ID = newID()
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	err := s.Update(func(items []Recurring) ([]Recurring, error) {
+		items = slices.DeleteFunc(items, func(x Recurring) bool { return x.ChatID == r.ChatID })
+		return append(items, r), nil
+	})
+	return r, err
+}
+
+// Remove deletes a recurring reminder by ID.
+func (s *RecurringStore) Remove(id string) (Recurring, error) {
+	var removed Recurring
+	err := s.Update(func(items []Recurring) ([]Recurring, error) {
+		i := slices.IndexFunc(items, func(r Recurring) bool { return r.ID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("recurring reminder not found: %s", id)
+		}
+		removed = items[i]
+		return slices.Delete(items, i, i+1), nil
+	})
+	return removed, err
+}
+
+// Update loads the file under the lock, applies fn, and saves the result.
+// Nothing is written if fn returns an error.
+func (s *RecurringStore) Update(fn func([]Recurring) ([]Recurring, error)) error {
+	unlock, err := lockFile(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	items, err := s.load()
+	if err != nil {
+		return err
+	}
+	items, err = fn(items)
+	if err != nil {
+		return err
+	}
+	slices.SortStableFunc(items, func(a, b Recurring) int { return a.Next.Compare(b.Next) })
+	data, err := json.MarshalIndent(recurringFile{Reminders: items}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write recurring reminders: %w", err)
+	}
+	return nil
+}
+
+func (s *RecurringStore) load() ([]Recurring, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recurring reminders: %w", err)
+	}
+	var f recurringFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse recurring reminders %s: %w", s.path, err)
+	}
+	return f.Reminders, nil
+}
+
+// ReminderSetter sets a chat's reminder; *api.RemindersService implements it.
+type ReminderSetter interface {
+	Set(ctx context.Context, chatID string, at time.Time) error
+}
+
+// RearmResult is the outcome of re-arming one recurring reminder.
+type RearmResult struct {
+	Reminder Recurring
+	Err      error
+}
+
+// Rearm sets the next occurrence of every recurring reminder whose armed
+// occurrence has passed at now. A failed reminder keeps its old Next and is
+// tried again on the next call.
+func Rearm(ctx context.Context, s *RecurringStore, setter ReminderSetter, now time.Time) ([]RearmResult, error) {
+	items, err := s.Items()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []RearmResult
+	for _, r := range items {
+		if !r.Due(now) {
+			continue
+		}
+		next := r.Every.Next(now)
+		setErr := setter.Set(ctx, r.ChatID, next)
+		err := s.Update(func(items []Recurring) ([]Recurring, error) {
+			for i := range items {
+				if items[i].ID != r.ID {
+					continue
+				}
+				if setErr != nil {
+					items[i].LastError = setErr.Error()
+				} else {
+					items[i].Next = next
+					items[i].LastError = ""
+				}
+				r = items[i]
+			}
+			return items, nil
+		})
+		if err != nil {
+			return results, fmt.Errorf("failed to record reminder %s: %w", r.ID, err)
+		}
+		results = append(results, RearmResult{Reminder: r, Err: setErr})
+	}
+	return results, nil
+}
@@ -0,0 +1,131 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseEvery(t *testing.T) {
+	tests := []struct {
+		spec, at string
+		days     []time.Weekday
+		hour     int
+		minute   int
+		str      string
+	}{
+		{"weekday 9:00", "", weekdays, 9, 0, "weekdays at 09:00"},
+		{"weekdays", "5:30pm", weekdays, 17, 30, "weekdays at 17:30"},
+		{"daily", "", everyDay, 9, 0, "daily at 09:00"},
+		{"weekend 10 am", "", weekend, 10, 0, "weekends at 10:00"},
+		{"mon,wed,fri 17:30", "", []time.Weekday{time.Monday, time.Wednesday, time.Friday}, 17, 30, "Mon,Wed,Fri at 17:30"},
+		{"tuesdays", "noon", []time.Weekday{time.Tuesday}, 12, 0, "Tue at 12:00"},
+	}
+	for _, tt := range tests {
+		r, err := ParseEvery(tt.spec, tt.at, "")
+		if err != nil {
+			t.Errorf("ParseEvery(%q, %q) error: %v", tt.spec, tt.at, err)
+			continue
+		}
+		if !slices.Equal(r.Days, tt.days) || r.Hour != tt.hour || r.Minute != tt.minute {
+			t.Errorf("ParseEvery(%q, %q) = %+v", tt.spec, tt.at, r)
+		}
+		if s := r.String(); s != tt.str {
+			t.Errorf("String() = %q, want %q", s, tt.str)
+		}
+	}
+
+	for _, bad := range [][3]string{{"", "", ""}, {"fortnightly", "", ""}, {"weekday 25:00", "", ""}, {"daily", "in 2h", ""}, {"daily", "", "Mars/Olympus"}} {
+		if _, err := ParseEvery(bad[0], bad[1], bad[2]); err == nil {
+			t.Errorf("ParseEvery(%q, %q, %q) should fail", bad[0], bad[1], bad[2])
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	r := Recurrence{Days: weekdays, Hour: 9, Zone: "America/New_York"}
+
+	fri := time.Date(2025, 3, 7, 8, 0, 0, 0, ny)
+	if got, want := r.Next(fri), time.Date(2025, 3, 7, 9, 0, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("Next(Fri 8:00) = %v, want %v", got, want)
+	}
+	// Exactly at an occurrence moves on, skipping the weekend (and the DST
+	// change on Sunday).
+	if got, want := r.Next(time.Date(2025, 3, 7, 9, 0, 0, 0, ny)), time.Date(2025, 3, 10, 9, 0, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("Next(Fri 9:00) = %v, want %v", got, want)
+	}
+}
+
+type fakeSetter struct {
+	calls map[string]time.Time
+	fail  string
+}
+
+func (f *fakeSetter) Set(_ context.Context, chatID string, at time.Time) error {
+	if chatID == f.fail {
+		return errors.New("boom")
+	}
+	f.calls[chatID] = at
+	return nil
+}
+
+func TestRearm(t *testing.T) {
+	s := OpenRecurring(filepath.Join(t.TempDir(), RecurringFileName))
+	daily := Recurrence{Days: everyDay, Hour: 9, Zone: "UTC"}
+	now := time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)
+
+	passed, _ := s.Add(Recurring{ChatID: "c1", Every: daily, Next: now.Add(-3 * time.Hour)})
+	armed, _ := s.Add(Recurring{ChatID: "c2", Every: daily, Next: now.Add(21 * time.Hour)})
+	failing, _ := s.Add(Recurring{ChatID: "c3", Every: daily})
+	// A second rule for a chat replaces the first.
+	if _, err := s.Add(Recurring{ChatID: "c2", Every: daily, Next: now.Add(21 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	setter := &fakeSetter{calls: map[string]time.Time{}, fail: "c3"}
+	results, err := Rearm(context.Background(), s, setter, now)
+	if err != nil {
+		t.Fatalf("Rearm() error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2 (c1 and c3): %+v", len(results), results)
+	}
+
+	tomorrow := time.Date(2025, 3, 6, 9, 0, 0, 0, time.UTC)
+	if !setter.calls["c1"].Equal(tomorrow) || len(setter.calls) != 1 {
+		t.Errorf("Set calls = %v, want only c1 at %v", setter.calls, tomorrow)
+	}
+
+	items, _ := s.Items()
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3", len(items))
+	}
+	for _, r := range items {
+		switch r.ID {
+		case passed.ID:
+			if !r.Next.Equal(tomorrow) || r.LastError != "" {
+				t.Errorf("re-armed reminder = %+v", r)
+			}
+		case failing.ID:
+			if !r.Next.IsZero() || r.LastError != "boom" {
+				t.Errorf("failed reminder = %+v, want unarmed with the error", r)
+			}
+		case armed.ID:
+			t.Error("replaced reminder is still stored")
+		}
+	}
+
+	if _, err := s.Remove(failing.ID); err != nil {
+		t.Errorf("Remove() error: %v", err)
+	}
+	if _, err := s.Remove(failing.ID); err == nil {
+		t.Error("second Remove() succeeded")
+	}
+}
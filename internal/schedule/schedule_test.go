@@ -0,0 +1,176 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+func openTestQueue(t *testing.T) *Queue {
+	t.Helper()
+	return Open(filepath.Join(t.TempDir(), FileName))
+}
+
+func TestQueueAddListCancel(t *testing.T) {
+	q := openTestQueue(t)
+	base := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	late, err := q.Add(Item{ChatID: "c1", Text: "later", SendAt: base.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	early, _ := q.Add(Item{ChatID: "c2", Text: "sooner", SendAt: base})
+	if late.ID == "" || late.ID == early.ID || late.Status != StatusPending {
+		t.Fatalf("Add() = %+v, want a pending item with a unique ID", late)
+	}
+
+	// A fresh handle sees the same queue, ordered by send time.
+	items, err := Open(q.Path()).Items()
+	if err != nil {
+		t.Fatalf("Items() error: %v", err)
+	}
+	if len(items) != 2 || items[0].ID != early.ID {
+		t.Fatalf("Items() = %+v, want early item first", items)
+	}
+
+	if _, err := q.Cancel(early.ID); err != nil {
+		t.Fatalf("Cancel() error: %v", err)
+	}
+	if _, err := q.Cancel(early.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Cancel() error = %v, want ErrNotFound", err)
+	}
+	if items, _ := q.Items(); len(items) != 1 {
+		t.Errorf("len(Items()) = %d after cancel, want 1", len(items))
+	}
+}
+
+type fakeSender struct {
+	sent []string
+	err  error
+}
+
+func (s *fakeSender) Send(_ context.Context, chatID string, req api.SendMessageRequest) (*api.SendMessageResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.sent = append(s.sent, chatID+":"+req.Text)
+	return &api.SendMessageResponse{MessageID: "msg-" + req.Text}, nil
+}
+
+func TestDispatchSendsDueItems(t *testing.T) {
+	q := openTestQueue(t)
+	now := time.Now()
+	due, _ := q.Add(Item{ChatID: "c1", Text: "due", SendAt: now.Add(-time.Minute)})
+	_, _ = q.Add(Item{ChatID: "c1", Text: "future", SendAt: now.Add(time.Hour)})
+
+	sender := &fakeSender{}
+	results, err := Dispatch(context.Background(), q, sender, now)
+	if err != nil {
+		t.Fatalf("Dispatch() error: %v", err)
+	}
+	if len(results) != 1 || results[0].Err != nil || len(sender.sent) != 1 || sender.sent[0] != "c1:due" {
+		t.Fatalf("Dispatch() = %+v, sent %v; want only the due item", results, sender.sent)
+	}
+
+	items, _ := q.Items()
+	if items[0].ID != due.ID || items[0].Status != StatusSent || items[0].MessageID != "msg-due" {
+		t.Errorf("delivered item = %+v, want sent with message ID", items[0])
+	}
+
+	// Running again sends nothing new.
+	if results, _ := Dispatch(context.Background(), q, sender, now); len(results) != 0 {
+		t.Errorf("second Dispatch() sent %d items", len(results))
+	}
+}
+
+func TestDispatchKeepsFailuresForRetry(t *testing.T) {
+	q := openTestQueue(t)
+	now := time.Now()
+	it, _ := q.Add(Item{ChatID: "c1", Text: "hi", SendAt: now})
+
+	sender := &fakeSender{err: errors.New("Beeper Desktop is not running")}
+	results, err := Dispatch(context.Background(), q, sender, now)
+	if err != nil || len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("Dispatch() = %+v, %v; want one failed result", results, err)
+	}
+
+	items, _ := q.Items()
+	if items[0].Status != StatusFailed || items[0].Attempts != 1 || items[0].LastError == "" {
+		t.Fatalf("failed item = %+v", items[0])
+	}
+
+	// Not retried before the backoff elapses, then retried.
+	sender.err = nil
+	if results, _ := Dispatch(context.Background(), q, sender, now.Add(30*time.Second)); len(results) != 0 {
+		t.Error("retried before backoff")
+	}
+	if results, _ := Dispatch(context.Background(), q, sender, now.Add(2*time.Minute)); len(results) != 1 || results[0].Err != nil {
+		t.Errorf("retry after backoff = %+v", results)
+	}
+	if items, _ := q.Items(); items[0].ID != it.ID || items[0].Status != StatusSent {
+		t.Errorf("item after retry = %+v", items[0])
+	}
+}
+
+func TestDispatchGivesUpAfterMaxAttempts(t *testing.T) {
+	q := openTestQueue(t)
+	now := time.Now()
+	it, _ := q.Add(Item{ChatID: "c1", Text: "hi", SendAt: now})
+	sender := &fakeSender{err: errors.New("boom")}
+
+	for i := range MaxAttempts + 2 {
+		_, _ = Dispatch(context.Background(), q, sender, now.Add(time.Duration(i)*24*time.Hour))
+	}
+	items, _ := q.Items()
+	if items[0].Attempts != MaxAttempts {
+		t.Errorf("Attempts = %d, want %d", items[0].Attempts, MaxAttempts)
+	}
+
+	if err := q.Retry(it.ID); err != nil {
+		t.Fatalf("Retry() error: %v", err)
+	}
+	if items, _ := q.Items(); !items[0].Due(now) {
+		t.Error("Retry() should make the item due again")
+	}
+}
+
+func TestDispatchDoesNotResendInterruptedItems(t *testing.T) {
+	q := openTestQueue(t)
+	now := time.Now()
+	it, _ := q.Add(Item{ChatID: "c1", Text: "hi", SendAt: now.Add(-time.Hour)})
+	// A dispatcher claimed the item and died before recording the result.
+	err := q.Update(func(items []Item) ([]Item, error) {
+		items[0].Status = StatusSending
+		items[0].Attempts = 1
+		items[0].NextAttempt = now.Add(-time.Hour)
+		return items, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &fakeSender{}
+	for _, at := range []time.Time{now, now.Add(24 * time.Hour)} {
+		if results, err := Dispatch(context.Background(), q, sender, at); err != nil || len(results) != 0 {
+			t.Fatalf("Dispatch() = %+v, %v; want nothing sent", results, err)
+		}
+	}
+	if len(sender.sent) != 0 {
+		t.Fatalf("interrupted item was sent again: %v", sender.sent)
+	}
+	items, _ := q.Items()
+	if items[0].Status != StatusInterrupted || items[0].LastError == "" {
+		t.Fatalf("item = %+v, want interrupted with an explanation", items[0])
+	}
+
+	if err := q.Retry(it.ID); err != nil {
+		t.Fatalf("Retry() error: %v", err)
+	}
+	if results, _ := Dispatch(context.Background(), q, sender, now.Add(25*time.Hour)); len(results) != 1 || len(sender.sent) != 1 {
+		t.Errorf("Dispatch() after Retry() = %+v, want the item sent", results)
+	}
+}
@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/99designs/keyring"
+)
+
+// Backend names accepted by WithBackend.
+const (
+	BackendAuto          = "auto"
+	BackendKeychain      = "keychain"
+	BackendSecretService = "secret-service"
+	BackendKWallet       = "kwallet"
+	BackendPass          = "pass"
+	BackendFile          = "file"
+	BackendWinCred       = "wincred"
+)
+
+// knownBackends lists every selectable backend in preference order.
+var knownBackends = []string{
+	BackendKeychain,
+	BackendWinCred,
+	BackendSecretService,
+	BackendKWallet,
+	BackendPass,
+	BackendFile,
+}
+
+// defaultBackends returns the backends tried when none is configured. The
+// file backend is never picked implicitly since it needs a password.
+func defaultBackends() []keyring.BackendType {
+	switch runtime.GOOS {
+	case "darwin":
+		return []keyring.BackendType{keyring.KeychainBackend}
+	case "windows":
+		return []keyring.BackendType{keyring.WinCredBackend}
+	default:
+		return []keyring.BackendType{keyring.SecretServiceBackend, keyring.KWalletBackend}
+	}
+}
+
+func resolveBackends(name string) ([]keyring.BackendType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == BackendAuto {
+		return defaultBackends(), nil
+	}
+	for _, known := range knownBackends {
+		if name == known {
+			return []keyring.BackendType{keyring.BackendType(name)}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown keyring backend %q (valid: %s, %s)", name, BackendAuto, strings.Join(knownBackends, ", "))
+}
+
+// BackendStatus reports whether a backend can be used on this machine.
+type BackendStatus struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Default   bool   `json:"default"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Backends probes every known backend by trying to open it. Backends not
+// compiled for this OS, or whose service isn't running, are reported as
+// unavailable with a reason. fileDir is the directory for the file backend.
+func Backends(fileDir string) []BackendStatus {
+	compiled := make(map[keyring.BackendType]bool)
+	for _, b := range keyring.AvailableBackends() {
+		compiled[b] = true
+	}
+	defaults := make(map[keyring.BackendType]bool)
+	for _, b := range defaultBackends() {
+		defaults[b] = true
+	}
+
+	statuses := make([]BackendStatus, 0, len(knownBackends))
+	for _, name := range knownBackends {
+		bt := keyring.BackendType(name)
+		status := BackendStatus{Name: name, Default: defaults[bt]}
+		if compiled[bt] {
+			kc := baseKeyringConfig()
+			kc.AllowedBackends = []keyring.BackendType{bt}
+			kc.FileDir = fileDir
+			kc.FilePasswordFunc = keyring.FixedStringPrompt("")
+			_, err := keyring.Open(kc)
+			status.Available = err == nil
+		}
+		if !status.Available {
+			status.Reason = unavailableReason(name)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// unavailableReason explains why a backend could not be opened. The keyring
+// library reports every failure as "not available", so this checks the
+// usual causes itself.
+func unavailableReason(name string) string {
+	switch name {
+	case BackendKeychain:
+		if runtime.GOOS != "darwin" {
+			return "macOS only"
+		}
+	case BackendWinCred:
+		if runtime.GOOS != "windows" {
+			return "Windows only"
+		}
+	case BackendSecretService, BackendKWallet:
+		if runtime.GOOS != "linux" {
+			return "Linux only"
+		}
+		return "no D-Bus session bus, or the service is not running"
+	case BackendPass:
+		if _, err := exec.LookPath("pass"); err != nil {
+			return "pass command not found"
+		}
+		return "password store could not be opened"
+	}
+	return "could not be opened"
+}
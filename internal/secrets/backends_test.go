@@ -0,0 +1,80 @@
+package secrets
+
+import (
+	"testing"
+)
+
+func TestResolveBackends(t *testing.T) {
+	for _, name := range []string{"", "auto", " AUTO "} {
+		backends, err := resolveBackends(name)
+		if err != nil {
+			t.Fatalf("resolveBackends(%q) error: %v", name, err)
+		}
+		if len(backends) == 0 {
+			t.Errorf("resolveBackends(%q) returned no defaults", name)
+		}
+	}
+
+	backends, err := resolveBackends("secret-service")
+	if err != nil {
+		t.Fatalf("resolveBackends(secret-service) error: %v", err)
+	}
+	if len(backends) != 1 || string(backends[0]) != BackendSecretService {
+		t.Errorf("resolveBackends(secret-service) = %v", backends)
+	}
+
+	if _, err := resolveBackends("gnome"); err == nil {
+		t.Error("resolveBackends(gnome) should fail")
+	}
+}
+
+func TestFileBackendWithPassword(t *testing.T) {
+	dir := t.TempDir()
+	open := func(password string) Store {
+		t.Helper()
+		store, err := NewStore(
+			WithBackend(BackendFile),
+			WithFileDir(dir),
+			WithPasswordFunc(func(string) (string, error) { return password, nil }),
+		)
+		if err != nil {
+			t.Fatalf("NewStore() error: %v", err)
+		}
+		return store
+	}
+
+	if err := open("s3cret").Set("ci", Credentials{Token: "tok"}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	got, err := open("s3cret").Get("ci")
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if got.Token != "tok" {
+		t.Errorf("Token = %q, want 'tok'", got.Token)
+	}
+
+	if _, err := open("wrong").Get("ci"); err == nil {
+		t.Error("Get() with the wrong password should fail")
+	}
+}
+
+func TestBackendsReportsFile(t *testing.T) {
+	var file *BackendStatus
+	statuses := Backends(t.TempDir())
+	for i := range statuses {
+		if statuses[i].Name == BackendFile {
+			file = &statuses[i]
+		}
+	}
+	if file == nil {
+		t.Fatal("Backends() did not report the file backend")
+	}
+	if !file.Available {
+		t.Errorf("file backend should be available: %s", file.Reason)
+	}
+	if file.Default {
+		t.Error("file backend should never be a default")
+	}
+}
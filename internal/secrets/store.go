@@ -13,6 +13,8 @@ const serviceName = "beeper-cli"
 type Credentials struct {
 	Name      string    `json:"name,omitempty"`
 	Token     string    `json:"token"`
+	BaseURL   string    `json:"base_url,omitempty"` // API endpoint this token belongs to
+	Account   string    `json:"account,omitempty"`  // Default --account filter
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -25,6 +27,8 @@ type Store interface {
 
 type AccountInfo struct {
 	Name      string
+	BaseURL   string
+	Account   string
 	CreatedAt time.Time
 }
 
@@ -36,26 +40,49 @@ type StoreOption func(*storeConfig)
 
 type storeConfig struct {
 	fileBackendDir string
+	backend        string
+	fileDir        string
+	passwordFunc   func(prompt string) (string, error)
 }
 
+// WithFileBackend stores credentials in an encrypted file under dir with a
+// fixed password. It is intended for tests; use WithBackend(BackendFile) for
+// real file storage.
 func WithFileBackend(dir string) StoreOption {
 	return func(c *storeConfig) {
 		c.fileBackendDir = dir
 	}
 }
 
+// WithBackend selects a credential backend by name (see Backends). An empty
+// name or BackendAuto picks the platform default.
+func WithBackend(name string) StoreOption {
+	return func(c *storeConfig) {
+		c.backend = name
+	}
+}
+
+// WithFileDir sets the directory used by the encrypted file backend.
+func WithFileDir(dir string) StoreOption {
+	return func(c *storeConfig) {
+		c.fileDir = dir
+	}
+}
+
+// WithPasswordFunc sets how the encrypted file backend obtains its password.
+func WithPasswordFunc(fn func(prompt string) (string, error)) StoreOption {
+	return func(c *storeConfig) {
+		c.passwordFunc = fn
+	}
+}
+
 func NewStore(opts ...StoreOption) (Store, error) {
 	cfg := &storeConfig{}
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
-	kc := keyring.Config{
-		ServiceName:                    serviceName,
-		KeychainTrustApplication:       true,
-		KeychainSynchronizable:         false,
-		KeychainAccessibleWhenUnlocked: true,
-	}
+	kc := baseKeyringConfig()
 
 	if cfg.fileBackendDir != "" {
 		// File backend is only used for testing
@@ -65,18 +92,41 @@ func NewStore(opts ...StoreOption) (Store, error) {
 			return "test-password", nil
 		}
 	} else {
-		// Production: use system keychain
-		kc.AllowedBackends = []keyring.BackendType{keyring.KeychainBackend}
+		backends, err := resolveBackends(cfg.backend)
+		if err != nil {
+			return nil, err
+		}
+		kc.AllowedBackends = backends
+		kc.FileDir = cfg.fileDir
+		kc.FilePasswordFunc = cfg.passwordFunc
+		if kc.FilePasswordFunc == nil {
+			kc.FilePasswordFunc = keyring.TerminalPrompt
+		}
 	}
 
 	ring, err := keyring.Open(kc)
 	if err != nil {
+		if cfg.backend != "" && cfg.backend != BackendAuto {
+			return nil, fmt.Errorf("failed to open keyring backend %q: %w", cfg.backend, err)
+		}
 		return nil, fmt.Errorf("failed to open keyring: %w", err)
 	}
 
 	return &keyringStore{ring: ring}, nil
 }
 
+func baseKeyringConfig() keyring.Config {
+	return keyring.Config{
+		ServiceName:                    serviceName,
+		KeychainTrustApplication:       true,
+		KeychainSynchronizable:         false,
+		KeychainAccessibleWhenUnlocked: true,
+		PassPrefix:                     serviceName,
+		KWalletAppID:                   serviceName,
+		KWalletFolder:                  serviceName,
+	}
+}
+
 func (s *keyringStore) Get(name string) (*Credentials, error) {
 	item, err := s.ring.Get(name)
 	if err != nil {
@@ -125,6 +175,8 @@ func (s *keyringStore) List() ([]AccountInfo, error) {
 		}
 		accounts = append(accounts, AccountInfo{
 			Name:      key,
+			BaseURL:   creds.BaseURL,
+			Account:   creds.Account,
 			CreatedAt: creds.CreatedAt,
 		})
 	}
@@ -0,0 +1,290 @@
+// Package templates renders reusable message templates: Go text/template
+// files kept in a directory under the config dir.
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/config"
+)
+
+// Ext is the file extension of template files.
+const Ext = ".tmpl"
+
+// Starter is the content of a template created by Create without text.
+const Starter = `{{/* Daily standup update. Vars: yesterday, today */}}
+Hi {{.FirstNames}}! Update for {{date "Mon Jan 2" .Now}}:
+- Yesterday: {{.Vars.yesterday}}
+- Today: {{.Vars.today}}
+`
+
+// ErrNotFound is returned for a template name with no file.
+var ErrNotFound = errors.New("template not found")
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Dir returns the templates directory under config.ConfigDir.
+func Dir() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// Info describes a template file.
+type Info struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Summary is the template's leading {{/* comment */}}, if any.
+	Summary string `json:"summary,omitempty"`
+}
+
+// List returns the templates in dir, sorted by name. A missing directory
+// holds no templates.
+func List(dir string) ([]Info, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates: %w", err)
+	}
+
+	var infos []Info
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), Ext)
+		if e.IsDir() || !ok || !validName.MatchString(name) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		info := Info{Name: name, Path: path}
+		if data, err := os.ReadFile(path); err == nil {
+			info.Summary = summary(string(data))
+		}
+		infos = append(infos, info)
+	}
+	slices.SortFunc(infos, func(a, b Info) int { return strings.Compare(a.Name, b.Name) })
+	return infos, nil
+}
+
+// summary extracts a leading {{/* comment */}}.
+func summary(text string) string {
+	text = strings.TrimSpace(text)
+	rest, ok := strings.CutPrefix(text, "{{/*")
+	if !ok {
+		rest, ok = strings.CutPrefix(text, "{{- /*")
+	}
+	if !ok {
+		return ""
+	}
+	comment, _, ok := strings.Cut(rest, "*/")
+	if !ok {
+		return ""
+	}
+	return strings.Join(strings.Fields(comment), " ")
+}
+
+// Path returns the file for template name in dir.
+func Path(dir, name string) (string, error) {
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("invalid template name %q: use letters, digits, dots, dashes and underscores", name)
+	}
+	return filepath.Join(dir, name+Ext), nil
+}
+
+// Read returns the source of template name.
+func Read(dir, name string) (string, error) {
+	path, err := Path(dir, name)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s (looked in %s)", ErrNotFound, name, dir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read template: %w", err)
+	}
+	return string(data), nil
+}
+
+// Load reads and parses template name.
+func Load(dir, name string) (*Template, error) {
+	text, err := Read(dir, name)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(name, text)
+}
+
+// Create writes a new template, refusing to overwrite an existing one. An
+// empty text writes Starter.
+func Create(dir, name, text string) (string, error) {
+	path, err := Path(dir, name)
+	if err != nil {
+		return "", err
+	}
+	if text == "" {
+		text = Starter
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create templates dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if os.IsExist(err) {
+		return "", fmt.Errorf("template %s already exists: %s", name, path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create template: %w", err)
+	}
+	if _, err := f.WriteString(text); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("failed to write template: %w", err)
+	}
+	return path, f.Close()
+}
+
+// Template is a parsed message template.
+type Template struct {
+	tmpl *template.Template
+}
+
+// Parse parses template text. Referencing a missing --var is an error at
+// render time rather than silently printing "<no value>".
+func Parse(name, text string) (*Template, error) {
+	t, err := template.New(name).Option("missingkey=error").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	return &Template{tmpl: t}, nil
+}
+
+// Render executes the template and trims surrounding whitespace.
+func (t *Template) Render(data Data) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Data is what templates see.
+type Data struct {
+	ID      string
+	Title   string
+	Network string
+	Type    string
+	// Participants are the other people in the chat, without you.
+	Participants []Person
+	// Vars holds --var key=value pairs.
+	Vars map[string]string
+	Now  time.Time
+}
+
+// Person is a chat participant.
+type Person struct {
+	FullName  string
+	FirstName string
+	Username  string
+}
+
+// NewData builds template data for chat, which may be nil when no chat is
+// known.
+func NewData(chat *api.Chat, vars map[string]string, now time.Time) Data {
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	d := Data{Vars: vars, Now: now}
+	if chat == nil {
+		return d
+	}
+	d.ID, d.Title, d.Type = chat.ID, chat.Title, chat.Type
+	d.Network = chat.Network
+	if d.Network == "" {
+		d.Network = chat.AccountID
+	}
+	if chat.Participants != nil {
+		for _, p := range chat.Participants.Items {
+			if p.IsSelf {
+				continue
+			}
+			d.Participants = append(d.Participants, Person{
+				FullName:  p.FullName,
+				FirstName: firstName(p),
+				Username:  p.Username,
+			})
+		}
+	}
+	return d
+}
+
+// FirstNames lists the participants' first names in prose: "Ann",
+// "Ann and Bob", "Ann, Bob and Cy". A chat with no known participants
+// falls back to its title.
+func (d Data) FirstNames() string {
+	names := make([]string, 0, len(d.Participants))
+	for _, p := range d.Participants {
+		if p.FirstName != "" {
+			names = append(names, p.FirstName)
+		}
+	}
+	switch len(names) {
+	case 0:
+		return d.Title
+	case 1:
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+func firstName(p api.Participant) string {
+	if f := strings.Fields(p.FullName); len(f) > 0 {
+		return f[0]
+	}
+	return p.Username
+}
+
+// ParseVars turns key=value flags into a map.
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid --var %q: use key=value", pair)
+		}
+		vars[k] = v
+	}
+	return vars, nil
+}
+
+// funcs are the helpers available in templates.
+var funcs = template.FuncMap{
+	// date formats a time with a Go layout: {{date "Mon Jan 2" .Now}}.
+	"date": func(layout string, t time.Time) string { return t.Format(layout) },
+	// addDays shifts a time by whole days: {{date "Jan 2" (addDays 7 .Now)}}.
+	"addDays": func(n int, t time.Time) time.Time { return t.AddDate(0, 0, n) },
+	// weekday names the day of a time: {{weekday .Now}}.
+	"weekday": func(t time.Time) string { return t.Weekday().String() },
+	// default returns def when value is empty, e.g. for an optional var:
+	// {{default "n/a" (index .Vars "note")}}.
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
@@ -0,0 +1,107 @@
+package templates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+func testChat() *api.Chat {
+	return &api.Chat{
+		ID:      "chat-1",
+		Title:   "Standup",
+		Network: "slack",
+		Type:    "group",
+		Participants: &api.ParticipantList{Items: []api.Participant{
+			{FullName: "Me Myself", IsSelf: true},
+			{FullName: "Ann Lee"},
+			{FullName: "Bob Ray"},
+			{Username: "cy"},
+		}},
+	}
+}
+
+func TestRender(t *testing.T) {
+	tmpl, err := Parse("standup", `Hi {{.FirstNames}} in {{.Title}} ({{.Network}})!
+{{date "Mon Jan 2" .Now}} -> {{weekday (addDays 1 .Now)}}
+Blockers: {{default "none" (index .Vars "blockers")}}; ETA {{.Vars.eta}}
+`)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	now := time.Date(2025, 3, 7, 9, 0, 0, 0, time.UTC) // a Friday
+	got, err := tmpl.Render(NewData(testChat(), map[string]string{"eta": "5pm"}, now))
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	want := "Hi Ann, Bob and cy in Standup (slack)!\nFri Mar 7 -> Saturday\nBlockers: none; ETA 5pm"
+	if got != want {
+		t.Errorf("Render() =\n%q\nwant\n%q", got, want)
+	}
+
+	if _, err := tmpl.Render(NewData(testChat(), nil, now)); err == nil || !strings.Contains(err.Error(), "eta") {
+		t.Errorf("Render() with a missing var error = %v", err)
+	}
+}
+
+func TestFirstNamesFallsBackToTitle(t *testing.T) {
+	d := NewData(&api.Chat{Title: "Ops", AccountID: "tg"}, nil, time.Now())
+	if d.FirstNames() != "Ops" || d.Network != "tg" {
+		t.Errorf("FirstNames() = %q, Network = %q", d.FirstNames(), d.Network)
+	}
+}
+
+func TestCreateListLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "templates")
+
+	if infos, err := List(dir); err != nil || len(infos) != 0 {
+		t.Fatalf("List() on missing dir = %v, %v", infos, err)
+	}
+	if _, err := Create(dir, "standup", ""); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if _, err := Create(dir, "standup", "x"); err == nil {
+		t.Error("Create() should not overwrite")
+	}
+	if _, err := Create(dir, "../evil", "x"); err == nil {
+		t.Error("Create() should reject path names")
+	}
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600)
+
+	infos, err := List(dir)
+	if err != nil || len(infos) != 1 || infos[0].Name != "standup" {
+		t.Fatalf("List() = %+v, %v", infos, err)
+	}
+	if infos[0].Summary != "Daily standup update. Vars: yesterday, today" {
+		t.Errorf("Summary = %q", infos[0].Summary)
+	}
+
+	tmpl, err := Load(dir, "standup")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	got, err := tmpl.Render(NewData(testChat(), map[string]string{"yesterday": "a", "today": "b"}, time.Now()))
+	if err != nil || !strings.HasPrefix(got, "Hi Ann, Bob and cy!") {
+		t.Errorf("Render() = %q, %v", got, err)
+	}
+
+	if _, err := Load(dir, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	vars, err := ParseVars([]string{"a=1", "b=x=y", "c="})
+	if err != nil || vars["a"] != "1" || vars["b"] != "x=y" || vars["c"] != "" {
+		t.Errorf("ParseVars() = %v, %v", vars, err)
+	}
+	if _, err := ParseVars([]string{"novalue"}); err == nil {
+		t.Error("ParseVars() should reject a pair without '='")
+	}
+}
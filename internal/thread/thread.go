@@ -0,0 +1,278 @@
+// Package thread renders messages as a conversation: replies indented under
+// the message they answer, consecutive messages from one sender grouped
+// under a single header, and a separator line at each new day.
+package thread
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/outfmt"
+)
+
+// Rendering limits.
+const (
+	// groupGap splits a sender's run of messages into separate groups.
+	groupGap = 10 * time.Minute
+	// maxIndent caps nesting so deep reply chains stay readable.
+	maxIndent = 4
+	// quoteLen is how much of a quoted message is shown.
+	quoteLen = 80
+)
+
+// Node is a message and the replies to it that are in the same set.
+type Node struct {
+	Message api.Message `json:"message"`
+	Replies []*Node     `json:"replies,omitempty"`
+}
+
+// Build arranges messages into reply trees, oldest first. A message whose
+// parent is not among msgs becomes a root.
+func Build(msgs []api.Message) []*Node {
+	sorted := slices.Clone(msgs)
+	slices.SortStableFunc(sorted, compareMessages)
+
+	nodes := make(map[string]*Node, len(sorted))
+	for _, m := range sorted {
+		if _, dup := nodes[m.ID]; !dup {
+			nodes[m.ID] = &Node{Message: m}
+		}
+	}
+
+	var roots []*Node
+	seen := make(map[string]bool, len(sorted))
+	for _, m := range sorted {
+		if seen[m.ID] {
+			continue
+		}
+		seen[m.ID] = true
+		n := nodes[m.ID]
+		if parent := parentIn(nodes, m); parent != nil {
+			parent.Replies = append(parent.Replies, n)
+			continue
+		}
+		roots = append(roots, n)
+	}
+	return roots
+}
+
+// parentIn returns the node m replies to, if it is present. Self-replies and
+// cycles are treated as roots.
+func parentIn(nodes map[string]*Node, m api.Message) *Node {
+	if m.ReplyTo == nil || m.ReplyTo.ID == "" || m.ReplyTo.ID == m.ID {
+		return nil
+	}
+	parent := nodes[m.ReplyTo.ID]
+	if parent == nil || compareMessages(parent.Message, m) > 0 {
+		return nil
+	}
+	return parent
+}
+
+func compareMessages(a, b api.Message) int {
+	if c := a.Timestamp.Compare(b.Timestamp); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.SortKey, b.SortKey)
+}
+
+// Options controls rendering.
+type Options struct {
+	// Sender names a message's sender. Defaults to Message.Sender, then
+	// SenderID.
+	Sender func(api.Message) string
+	// Color enables ANSI colors.
+	Color bool
+	// Highlight marks one message, e.g. the one a thread was built around.
+	Highlight string
+	// Location is the zone times are shown in. Defaults to time.Local.
+	Location *time.Location
+}
+
+// Render writes msgs as a threaded conversation.
+func Render(w io.Writer, msgs []api.Message, opts Options) {
+	RenderTree(w, Build(msgs), opts)
+}
+
+// RenderTree writes reply trees built by Build.
+func RenderTree(w io.Writer, roots []*Node, opts Options) {
+	if opts.Sender == nil {
+		opts.Sender = defaultSender
+	}
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	r := &renderer{w: w, opts: opts}
+	r.level(roots, 0)
+}
+
+func defaultSender(m api.Message) string {
+	if m.IsMe {
+		return "You"
+	}
+	return cmp.Or(m.Sender, m.SenderID)
+}
+
+type renderer struct {
+	w    io.Writer
+	opts Options
+	day  string // last separator printed
+}
+
+// level renders sibling nodes. A group header is printed when the sender
+// changes, after a gap, on a new day, or after a message's replies.
+func (r *renderer) level(nodes []*Node, depth int) {
+	var prev *Node
+	for _, n := range nodes {
+		m := n.Message
+		local := m.Timestamp.In(r.opts.Location)
+
+		if depth == 0 {
+			if day := local.Format("2006-01-02"); day != r.day {
+				if r.day != "" {
+					r.line("")
+				}
+				r.day = day
+				r.line(r.color("── "+local.Format("Monday, January 2, 2006")+" ──", outfmt.Gray))
+				prev = nil
+			}
+		}
+
+		indent := strings.Repeat("    ", min(depth, maxIndent))
+		if prev == nil || len(prev.Replies) > 0 || !sameGroup(prev.Message, m, r.opts.Sender) {
+			header := r.color(r.opts.Sender(m), outfmt.Bold) + "  " + r.color(r.stamp(local, depth), outfmt.Gray)
+			if depth > 0 {
+				header = "↳ " + header
+			}
+			r.line(indent + header)
+		}
+
+		body := indent + "  "
+		if depth > 0 {
+			body += "  "
+		}
+		if depth == 0 && m.ReplyTo != nil {
+			// The parent isn't shown here, so quote it.
+			r.line(body + r.color("│ "+r.quote(*m.ReplyTo), outfmt.Gray))
+		}
+		marker := ""
+		if r.opts.Highlight != "" && m.ID == r.opts.Highlight {
+			marker = r.color("▶ ", outfmt.Yellow)
+		}
+		for i, line := range messageLines(m) {
+			if i == 0 {
+				line = marker + line
+			}
+			r.line(body + line)
+		}
+
+		r.level(n.Replies, depth+1)
+		prev = n
+	}
+}
+
+// stamp formats a message time. Nested replies may be days after the root,
+// so they carry the date when it differs from the current separator.
+func (r *renderer) stamp(local time.Time, depth int) string {
+	if depth > 0 && local.Format("2006-01-02") != r.day {
+		return local.Format("Jan 2, 3:04 PM")
+	}
+	return local.Format("3:04 PM")
+}
+
+func (r *renderer) quote(m api.Message) string {
+	// Reply targets are often partial; don't guess a sender for them.
+	sender := ""
+	if m.IsMe || m.Sender != "" || m.SenderID != "" {
+		sender = r.opts.Sender(m)
+	}
+	text := strings.Join(strings.Fields(m.Text), " ")
+	if text == "" && len(m.Attachments) > 0 {
+		text = "[attachment]"
+	}
+	if n := []rune(text); len(n) > quoteLen {
+		text = string(n[:quoteLen-1]) + "…"
+	}
+	if sender == "" {
+		return text
+	}
+	return sender + ": " + text
+}
+
+func (r *renderer) color(s, c string) string {
+	return outfmt.Colorize(s, c, r.opts.Color)
+}
+
+func (r *renderer) line(s string) {
+	_, _ = fmt.Fprintln(r.w, s)
+}
+
+func sameGroup(prev, m api.Message, sender func(api.Message) string) bool {
+	return sender(prev) == sender(m) && m.Timestamp.Sub(prev.Timestamp) <= groupGap
+}
+
+// messageLines is a message's text split into lines, followed by a line
+// per attachment.
+func messageLines(m api.Message) []string {
+	var lines []string
+	if m.Text != "" {
+		lines = strings.Split(m.Text, "\n")
+	}
+	for _, a := range m.Attachments {
+		name := cmp.Or(a.FileName, a.Type, "file")
+		lines = append(lines, "["+name+"]")
+	}
+	if len(lines) == 0 {
+		lines = []string{"(empty message)"}
+	}
+	return lines
+}
+
+// Chain returns the thread containing id: the root of its reply chain and
+// every reply below that root. It returns nil if id isn't in msgs.
+func Chain(msgs []api.Message, id string) *Node {
+	byID := make(map[string]api.Message, len(msgs))
+	for _, m := range msgs {
+		byID[m.ID] = m
+	}
+	target, ok := byID[id]
+	if !ok {
+		return nil
+	}
+
+	// Walk up to the root, guarding against cycles.
+	root := target
+	visited := map[string]bool{root.ID: true}
+	for root.ReplyTo != nil {
+		parent, ok := byID[root.ReplyTo.ID]
+		if !ok || visited[parent.ID] {
+			break
+		}
+		visited[parent.ID] = true
+		root = parent
+	}
+
+	for _, n := range Build(msgs) {
+		if found := find(n, root.ID); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func find(n *Node, id string) *Node {
+	if n.Message.ID == id {
+		return n
+	}
+	for _, c := range n.Replies {
+		if found := find(c, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package thread
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+)
+
+var base = time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC) // a Monday
+
+func msg(id, sender string, minutes int, text string, replyTo string) api.Message {
+	m := api.Message{ID: id, Sender: sender, Text: text, Timestamp: base.Add(time.Duration(minutes) * time.Minute)}
+	if replyTo != "" {
+		m.ReplyTo = &api.Message{ID: replyTo, Sender: "Zed", Text: "the original\nquestion"}
+	}
+	return m
+}
+
+func render(msgs []api.Message, opts Options) string {
+	var b bytes.Buffer
+	opts.Location = time.UTC
+	Render(&b, msgs, opts)
+	return b.String()
+}
+
+func TestRenderGroupsAndNestsReplies(t *testing.T) {
+	// Newest first, as the API returns them.
+	msgs := []api.Message{
+		msg("m5", "Ann", 60*24+5, "next day", ""),
+		msg("m4", "Ann", 30, "later", ""),
+		msg("m3", "Bob", 20, "agreed", "m1"),
+		msg("m2", "Ann", 2, "second\nline two", ""),
+		msg("m1", "Ann", 0, "first", ""),
+	}
+
+	got := render(msgs, Options{})
+	want := `── Monday, March 3, 2025 ──
+Ann  9:00 AM
+  first
+    ↳ Bob  9:20 AM
+        agreed
+Ann  9:02 AM
+  second
+  line two
+Ann  9:30 AM
+  later
+
+── Tuesday, March 4, 2025 ──
+Ann  9:05 AM
+  next day
+`
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderQuotesMissingParent(t *testing.T) {
+	msgs := []api.Message{msg("m9", "Bob", 0, "yes", "gone")}
+	msgs = append(msgs, api.Message{ID: "m10", IsMe: true, Timestamp: base.Add(time.Minute),
+		Attachments: []api.Attachment{{FileName: "plan.pdf"}}})
+
+	got := render(msgs, Options{Highlight: "m9"})
+	want := `── Monday, March 3, 2025 ──
+Bob  9:00 AM
+  │ Zed: the original question
+  ▶ yes
+You  9:01 AM
+  [plan.pdf]
+`
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestChain(t *testing.T) {
+	msgs := []api.Message{
+		msg("a", "Ann", 0, "root", ""),
+		msg("b", "Bob", 1, "reply", "a"),
+		msg("c", "Ann", 2, "reply to reply", "b"),
+		msg("d", "Cy", 3, "other reply", "a"),
+		msg("x", "Cy", 4, "unrelated", ""),
+	}
+
+	root := Chain(msgs, "c")
+	if root == nil || root.Message.ID != "a" {
+		t.Fatalf("Chain() root = %+v, want a", root)
+	}
+	if len(root.Replies) != 2 || root.Replies[0].Message.ID != "b" || root.Replies[0].Replies[0].Message.ID != "c" {
+		t.Errorf("Chain() replies = %+v", root.Replies)
+	}
+	if Chain(msgs, "missing") != nil {
+		t.Error("Chain() should be nil for an unknown ID")
+	}
+}
+
+func TestBuildIgnoresCycles(t *testing.T) {
+	a := msg("a", "Ann", 0, "x", "b")
+	b := msg("b", "Bob", 1, "y", "a")
+	roots := Build([]api.Message{a, b})
+	if len(roots) != 1 || roots[0].Message.ID != "a" || len(roots[0].Replies) != 1 {
+		t.Errorf("Build() = %+v", roots)
+	}
+	if Chain([]api.Message{a, b}, "b") == nil {
+		t.Error("Chain() should terminate on a cycle")
+	}
+}
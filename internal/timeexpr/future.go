@@ -0,0 +1,171 @@
+package timeexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Times of day used when an expression names a day but no time.
+const (
+	DefaultHour = 9  // "tomorrow", "next fri"
+	EndOfDay    = 17 // "eod", "eow"
+)
+
+// TimeFormats describes the forms ParseTime accepts.
+const TimeFormats = `"in 2h", +90m, 17:30, 9am, noon, "tomorrow 9am", "next fri", "mon 14:00", eod, eow, "2025-01-02 09:30" or RFC3339`
+
+// dateTimeLayouts are absolute date-times, read in the chosen zone.
+var dateTimeLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02 3:04pm",
+	"2006-01-02 3pm",
+}
+
+// clockLayouts are times of day.
+var clockLayouts = []string{"15:04", "3:04pm", "3pm"}
+
+var meridiemRe = regexp.MustCompile(`(\d)\s+(am|pm)\b`)
+
+// ParseTime reads a future time relative to now, in loc (time.Local when
+// nil):
+//
+//	+90m, in 2h, in 3 days      an offset from now
+//	17:30, 9am, noon            the next time the clock shows this
+//	tomorrow 9am, fri 14:00     a day and a time of day
+//	next fri                    a day after today, at DefaultHour
+//	eod, eow                    EndOfDay today, or on Friday
+//	2025-01-02 09:30            a date and time
+//	2025-01-02T09:30:00+01:00   RFC 3339 (its own offset wins over loc)
+//
+// Plain weekday names mean the soonest such day that is still ahead; "next"
+// skips today. The result may be in the past ("today 8am" in the evening);
+// callers that need a future time should check.
+func ParseTime(value string, now time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	now = now.In(loc)
+	raw := strings.TrimSpace(value)
+	s := strings.ToLower(strings.Join(strings.Fields(raw), " "))
+	s = meridiemRe.ReplaceAllString(s, "$1$2")
+	s = strings.TrimPrefix(strings.ReplaceAll(s, " at ", " "), "at ")
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+
+	if rest, ok := strings.CutPrefix(s, "+"); ok {
+		return after(now, rest, value)
+	}
+	if rest, ok := strings.CutPrefix(s, "in "); ok {
+		return after(now, rest, value)
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(raw)); err == nil {
+		return t, nil
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	today := midnight(now)
+	switch s {
+	case "now":
+		return now, nil
+	case "eod":
+		t := clockOn(today, EndOfDay, 0)
+		if !t.After(now) {
+			t = clockOn(today.AddDate(0, 0, 1), EndOfDay, 0)
+		}
+		return t, nil
+	case "eow":
+		days := (int(time.Friday) - int(today.Weekday()) + 7) % 7
+		t := clockOn(today.AddDate(0, 0, days), EndOfDay, 0)
+		if !t.After(now) {
+			t = clockOn(today.AddDate(0, 0, days+7), EndOfDay, 0)
+		}
+		return t, nil
+	}
+
+	if h, m, ok := ParseClock(s); ok {
+		t := clockOn(today, h, m)
+		if !t.After(now) {
+			t = clockOn(today.AddDate(0, 0, 1), h, m)
+		}
+		return t, nil
+	}
+
+	// A day, optionally followed by a time of day.
+	dayPart, h, m := s, DefaultHour, 0
+	if i := strings.LastIndex(s, " "); i > 0 {
+		if ch, cm, ok := ParseClock(s[i+1:]); ok {
+			dayPart, h, m = s[:i], ch, cm
+		}
+	}
+
+	switch dayPart {
+	case "today":
+		return clockOn(today, h, m), nil
+	case "tomorrow", "tmrw", "tmr":
+		return clockOn(today.AddDate(0, 0, 1), h, m), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", dayPart, loc); err == nil {
+		return clockOn(t, h, m), nil
+	}
+	which, name, ok := strings.Cut(dayPart, " ")
+	if !ok {
+		which, name = "", dayPart
+	}
+	if day, ok := Weekday(name); ok && (which == "" || which == "this" || which == "next") {
+		days := (int(day) - int(today.Weekday()) + 7) % 7
+		if which == "next" && days == 0 {
+			days = 7
+		}
+		t := clockOn(today.AddDate(0, 0, days), h, m)
+		if which != "next" && !t.After(now) {
+			t = clockOn(today.AddDate(0, 0, days+7), h, m)
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
+
+// clockOn is hour:minute on day's date, in day's zone. Unlike adding a
+// duration to midnight it keeps the wall-clock time across DST changes.
+func clockOn(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+// after adds an offset such as "90m", "2h30m", "3 days" or "1w" to now.
+func after(now time.Time, offset, value string) (time.Time, error) {
+	if d, err := time.ParseDuration(strings.ReplaceAll(offset, " ", "")); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	if m := offsetRe.FindStringSubmatch(offset); m != nil && !strings.HasSuffix(offset, "ago") {
+		if t, ok := shift(now, m[1], m[2], 1); ok && t.After(now) {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid offset %q, e.g. +90m, in 2h or in 3 days", value)
+}
+
+// ParseClock reads a time of day such as 17:30, 9am, 9:15 pm or noon.
+func ParseClock(s string) (hour, minute int, ok bool) {
+	s = meridiemRe.ReplaceAllString(strings.ToLower(strings.TrimSpace(s)), "$1$2")
+	switch s {
+	case "noon":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+	for _, layout := range clockLayouts {
+		if c, err := time.Parse(layout, s); err == nil {
+			return c.Hour(), c.Minute(), true
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,137 @@
+// Package timeexpr parses the date and time expressions accepted by flags:
+// past dates for filters ("3d", "last monday", "2024-Q3") and future times
+// for reminders and scheduling ("tomorrow 9am", "in 2h", "next fri").
+package timeexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats describes the accepted forms, for error messages and help text.
+const Formats = `2024-01-31, "2024-01-31 15:04", RFC3339, 2024-03, 2024, 2024-Q3, 3d, 2w, 6h, "2 months ago", today, yesterday, tomorrow, "last monday", "this week" or "last month"`
+
+var (
+	offsetRe  = regexp.MustCompile(`^(\d+)\s*([a-z]+?)s?(?:\s+ago)?$`)
+	quarterRe = regexp.MustCompile(`^(\d{4})-?q([1-4])$`)
+	yearRe    = regexp.MustCompile(`^\d{4}$`)
+)
+
+// ParseDate resolves value to the instant it starts, relative to now and in
+// now's location. Offsets count back from now ("3d" is 72 hours ago); named
+// days and periods start at midnight ("last monday", "2024-Q3" is July 1).
+// Weeks start on Monday.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(value), " "))
+	loc := now.Location()
+
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(value)); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02", "2006-01"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	if yearRe.MatchString(s) {
+		year, _ := strconv.Atoi(s)
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, loc), nil
+	}
+	if m := quarterRe.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		q, _ := strconv.Atoi(m[2])
+		return time.Date(year, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, loc), nil
+	}
+
+	today := midnight(now)
+	switch s {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if m := offsetRe.FindStringSubmatch(s); m != nil {
+		if t, ok := shift(now, m[1], m[2], -1); ok {
+			return t, nil
+		}
+	}
+
+	which, name, ok := strings.Cut(s, " ")
+	if !ok {
+		which, name = "last", s
+	}
+	if which == "last" || which == "this" {
+		if day, ok := Weekday(name); ok && which == "last" {
+			// The most recent such day before today.
+			back := (int(today.Weekday())-int(day)+6)%7 + 1
+			return today.AddDate(0, 0, -back), nil
+		}
+		prev := 0
+		if which == "last" {
+			prev = 1
+		}
+		switch name {
+		case "week":
+			monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+			return monday.AddDate(0, 0, -7*prev), nil
+		case "month":
+			return time.Date(today.Year(), today.Month()-time.Month(prev), 1, 0, 0, 0, 0, loc), nil
+		case "quarter":
+			first := time.Month(3*((int(today.Month())-1)/3) + 1)
+			return time.Date(today.Year(), first-time.Month(3*prev), 1, 0, 0, 0, 0, loc), nil
+		case "year":
+			return time.Date(today.Year()-prev, time.January, 1, 0, 0, 0, 0, loc), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
+
+// shift moves t by n units in direction sign (1 or -1). Minutes and hours
+// are exact durations; days and longer keep the wall-clock time.
+func shift(t time.Time, n, unit string, sign int) (time.Time, bool) {
+	count, err := strconv.Atoi(n)
+	if err != nil {
+		return time.Time{}, false
+	}
+	count *= sign
+	switch unit {
+	case "m", "min", "minute":
+		return t.Add(time.Duration(count) * time.Minute), true
+	case "h", "hr", "hour":
+		return t.Add(time.Duration(count) * time.Hour), true
+	case "d", "day":
+		return t.AddDate(0, 0, count), true
+	case "w", "wk", "week":
+		return t.AddDate(0, 0, 7*count), true
+	case "mo", "mon", "month":
+		return t.AddDate(0, count, 0), true
+	case "y", "yr", "year":
+		return t.AddDate(count, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// Weekday parses a full or abbreviated English day name.
+func Weekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(s), "."))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if s == full || len(s) >= 3 && strings.HasPrefix(full, s) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,160 @@
+package timeexpr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	// Wednesday, March 5, 2025 14:30 UTC.
+	now := time.Date(2025, time.March, 5, 14, 30, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2024-01-31", day(2024, time.January, 31)},
+		{"2024-01-31 15:04", time.Date(2024, time.January, 31, 15, 4, 0, 0, time.UTC)},
+		{"2024-01-31T15:04:00Z", time.Date(2024, time.January, 31, 15, 4, 0, 0, time.UTC)},
+		{"2024-03", day(2024, time.March, 1)},
+		{"2024", day(2024, time.January, 1)},
+		{"2024-Q3", day(2024, time.July, 1)},
+		{"2024q1", day(2024, time.January, 1)},
+		{"now", now},
+		{"today", day(2025, time.March, 5)},
+		{"Yesterday", day(2025, time.March, 4)},
+		{"tomorrow", day(2025, time.March, 6)},
+		{"3d", now.AddDate(0, 0, -3)},
+		{"3 days ago", now.AddDate(0, 0, -3)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"6h", now.Add(-6 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2mo", now.AddDate(0, -2, 0)},
+		{"1 year", now.AddDate(-1, 0, 0)},
+		{"last monday", day(2025, time.March, 3)},
+		{"last wednesday", day(2025, time.February, 26)},
+		{"thu", day(2025, time.February, 27)},
+		{"this week", day(2025, time.March, 3)},
+		{"last week", day(2025, time.February, 24)},
+		{"this month", day(2025, time.March, 1)},
+		{"last month", day(2025, time.February, 1)},
+		{"last quarter", day(2024, time.October, 1)},
+		{"this year", day(2025, time.January, 1)},
+		{"last year", day(2024, time.January, 1)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseDate(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	now := time.Date(2025, time.March, 5, 14, 30, 0, 0, time.UTC)
+	for _, in := range []string{"", "soon", "3 fortnights", "2024-Q5", "this monday", "next week"} {
+		if _, err := ParseDate(in, now); err == nil {
+			t.Errorf("ParseDate(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestWeekday(t *testing.T) {
+	for in, want := range map[string]time.Weekday{"monday": time.Monday, "Tue": time.Tuesday, "thurs": time.Thursday, "sun.": time.Sunday} {
+		if got, ok := Weekday(in); !ok || got != want {
+			t.Errorf("Weekday(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+	if _, ok := Weekday("mo"); ok {
+		t.Error("Weekday(\"mo\") matched; two letters are ambiguous")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	now := time.Date(2025, 3, 1, 15, 0, 0, 0, ny) // Saturday, 3pm in New York
+	at := func(d, h, m int) time.Time { return time.Date(2025, 3, d, h, m, 0, 0, ny) }
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"+90m", now.Add(90 * time.Minute)},
+		{"in 2h", now.Add(2 * time.Hour)},
+		{"in 2h30m", now.Add(150 * time.Minute)},
+		{"in 3 days", at(4, 15, 0)},
+		{"+1w", at(8, 15, 0)},
+		{"17:30", at(1, 17, 30)},
+		{"9am", at(2, 9, 0)},
+		{"9 AM", at(2, 9, 0)},
+		{"3pm", at(2, 15, 0)},
+		{"noon", at(2, 12, 0)},
+		{"tomorrow 9am", at(2, 9, 0)},
+		{"tomorrow at 17:45", at(2, 17, 45)},
+		{"tomorrow", at(2, DefaultHour, 0)},
+		{"today 8am", at(1, 8, 0)},
+		{"next fri", at(7, DefaultHour, 0)},
+		{"friday 10:30am", at(7, 10, 30)},
+		{"sat", at(8, DefaultHour, 0)},
+		{"sat 6pm", at(1, 18, 0)},
+		{"this sat 6pm", at(1, 18, 0)},
+		{"next sat 6pm", at(8, 18, 0)},
+		{"Monday 14:00", at(3, 14, 0)},
+		{"eod", at(1, EndOfDay, 0)},
+		{"eow", at(7, EndOfDay, 0)},
+		{"2025-03-04 08:15", at(4, 8, 15)},
+		{"2025-03-04 9am", at(4, 9, 0)},
+		{"2025-03-04", at(4, DefaultHour, 0)},
+		{"2025-03-04 17:00", at(4, 17, 0)},
+		{"2025-03-04T08:15:00Z", time.Date(2025, 3, 4, 8, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.in, now, ny)
+		if err != nil {
+			t.Errorf("ParseTime(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "tomorrowish", "+-5m", "25:00", "in 3 fortnights", "last fri", "5 days ago"} {
+		if _, err := ParseTime(bad, now, ny); err == nil {
+			t.Errorf("ParseTime(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseTimeKeepsWallClockAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	now := time.Date(2025, 3, 8, 12, 0, 0, 0, ny) // the day before clocks go forward
+	got, err := ParseTime("tomorrow 9am", now, ny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 3, 9, 9, 0, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("tomorrow 9am = %v, want %v", got, want)
+	}
+}
+
+func TestParseTimeRollsPastEndOfDay(t *testing.T) {
+	evening := time.Date(2025, 3, 7, 18, 0, 0, 0, time.UTC) // Friday
+	if got, _ := ParseTime("eod", evening, time.UTC); !got.Equal(time.Date(2025, 3, 8, EndOfDay, 0, 0, 0, time.UTC)) {
+		t.Errorf("eod after hours = %v, want tomorrow", got)
+	}
+	if got, _ := ParseTime("eow", evening, time.UTC); !got.Equal(time.Date(2025, 3, 14, EndOfDay, 0, 0, 0, time.UTC)) {
+		t.Errorf("eow on Friday evening = %v, want next Friday", got)
+	}
+}
@@ -0,0 +1,321 @@
+// Package watch matches incoming messages against filters and runs hooks
+// for them, remembering handled messages across restarts.
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/config"
+)
+
+// Payload is what hooks receive: the message plus its chat title and the
+// sender's display name.
+type Payload struct {
+	api.Message
+	ChatTitle  string `json:"chatTitle"`
+	SenderName string `json:"senderName"`
+}
+
+// NewPayload builds the hook payload for an event, naming the sender from
+// the event's chat.
+func NewPayload(ev api.MessageEvent) Payload {
+	p := Payload{Message: ev.Message, SenderName: api.SenderName(ev.Message, ev.Chat)}
+	if ev.Chat != nil {
+		p.ChatTitle = ev.Chat.Title
+	}
+	return p
+}
+
+// Filter selects which messages trigger hooks. Empty fields match anything.
+type Filter struct {
+	Match *regexp.Regexp
+	// Networks are network names or account IDs, compared case-insensitively.
+	Networks []string
+	// IncludeOwn also matches messages you sent.
+	IncludeOwn bool
+}
+
+// Matches reports whether ev passes the filter.
+func (f Filter) Matches(ev api.MessageEvent) bool {
+	if ev.IsMe && !f.IncludeOwn {
+		return false
+	}
+	if f.Match != nil && !f.Match.MatchString(ev.Text) {
+		return false
+	}
+	if len(f.Networks) > 0 {
+		network := ""
+		if ev.Chat != nil {
+			network = ev.Chat.Network
+		}
+		if !slices.ContainsFunc(f.Networks, func(n string) bool {
+			return strings.EqualFold(n, network) || strings.EqualFold(n, ev.AccountID)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hook is run for each matching message.
+type Hook interface {
+	Run(ctx context.Context, p Payload) error
+}
+
+// ExecHook runs a shell command with the payload as JSON on stdin and the
+// main fields in BEEPER_* environment variables.
+type ExecHook struct {
+	Command string
+	Timeout time.Duration
+}
+
+// DefaultHookTimeout bounds a single hook run.
+const DefaultHookTimeout = 30 * time.Second
+
+// Run implements Hook.
+func (h ExecHook) Run(ctx context.Context, p Payload) error {
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout(h.Timeout))
+	defer cancel()
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", h.Command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", h.Command)
+	}
+	c.Stdin = bytes.NewReader(data)
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(),
+		"BEEPER_MESSAGE_ID="+p.ID,
+		"BEEPER_CHAT_ID="+p.ChatID,
+		"BEEPER_CHAT_TITLE="+p.ChatTitle,
+		"BEEPER_SENDER="+p.SenderName,
+		"BEEPER_SENDER_ID="+p.SenderID,
+		"BEEPER_TEXT="+p.Text,
+	)
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("hook command failed: %w", err)
+	}
+	return nil
+}
+
+// WebhookHook POSTs the payload as JSON.
+type WebhookHook struct {
+	URL     string
+	Timeout time.Duration
+	Client  *http.Client
+}
+
+// Run implements Hook.
+func (h WebhookHook) Run(ctx context.Context, p Payload) error {
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout(h.Timeout))
+	defer cancel()
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "beeper-cli-watch")
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func hookTimeout(d time.Duration) time.Duration {
+	if d <= 0 {
+		return DefaultHookTimeout
+	}
+	return d
+}
+
+// stateLimit is how many handled message IDs a State remembers.
+const stateLimit = 5000
+
+// State remembers which messages a watcher has handled. It is saved as JSON
+// under config.DataDir/watch so a restarted watcher doesn't fire twice.
+type State struct {
+	// LastSeen is the newest handled message time; a restarted watcher
+	// catches up from here.
+	LastSeen time.Time `json:"lastSeen,omitzero"`
+	Handled  []string  `json:"handled"`
+
+	path string
+	set  map[string]bool
+}
+
+// StatePath returns the state file for a named watcher.
+func StatePath(name string) (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "watch", name+".json"), nil
+}
+
+// LoadState reads the state at path; a missing file gives an empty state.
+func LoadState(path string) (*State, error) {
+	s := &State{path: path, set: map[string]bool{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse watch state %s: %w", path, err)
+	}
+	for _, id := range s.Handled {
+		s.set[id] = true
+	}
+	return s, nil
+}
+
+// Seen reports whether a message was already handled.
+func (s *State) Seen(id string) bool {
+	return s.set[id]
+}
+
+// Mark records a handled message and saves the state.
+func (s *State) Mark(m api.Message) error {
+	if !s.set[m.ID] {
+		s.set[m.ID] = true
+		s.Handled = append(s.Handled, m.ID)
+		if over := len(s.Handled) - stateLimit; over > 0 {
+			for _, id := range s.Handled[:over] {
+				delete(s.set, id)
+			}
+			s.Handled = slices.Clone(s.Handled[over:])
+		}
+	}
+	if m.Timestamp.After(s.LastSeen) {
+		s.LastSeen = m.Timestamp
+	}
+	return s.save()
+}
+
+// save writes the state atomically.
+func (s *State) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create watch state dir: %w", err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write watch state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write watch state: %w", err)
+	}
+	return nil
+}
+
+// Watcher runs hooks for matching messages, once per message.
+type Watcher struct {
+	Filter Filter
+	Hooks  []Hook
+	State  *State
+	// Attempts is how many times a failing hook is tried (default 3).
+	Attempts int
+	// RetryDelay is the pause before the first retry; it doubles each time.
+	RetryDelay time.Duration
+	// Senders, if set, names senders from each chat's full participant
+	// list, which followed events don't always carry.
+	Senders *api.Senders
+}
+
+// Handle runs the hooks if ev matches and hasn't been handled before. It
+// reports whether the hooks ran. A message is recorded as handled even when
+// a hook fails after all attempts, so one bad message can't fire forever;
+// the failures are returned.
+func (w *Watcher) Handle(ctx context.Context, ev api.MessageEvent) (bool, error) {
+	if w.State.Seen(ev.ID) || !w.Filter.Matches(ev) {
+		return false, nil
+	}
+
+	p := NewPayload(ev)
+	if w.Senders != nil {
+		p.SenderName = w.Senders.Name(ctx, ev.Message, ev.Chat)
+	}
+	var errs []error
+	for _, hook := range w.Hooks {
+		if err := w.run(ctx, hook, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if ctx.Err() != nil {
+		// Interrupted: leave the message unhandled so a restart retries it.
+		return true, ctx.Err()
+	}
+	if err := w.State.Mark(ev.Message); err != nil {
+		errs = append(errs, err)
+	}
+	return true, errors.Join(errs...)
+}
+
+func (w *Watcher) run(ctx context.Context, hook Hook, p Payload) error {
+	attempts := max(w.Attempts, 1)
+	if w.Attempts == 0 {
+		attempts = 3
+	}
+	delay := w.RetryDelay
+	if delay <= 0 {
+		delay = time.Second
+	}
+
+	var err error
+	for i := range attempts {
+		if err = hook.Run(ctx, p); err == nil {
+			return nil
+		}
+		if i == attempts-1 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return err
+}
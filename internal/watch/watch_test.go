@@ -0,0 +1,190 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/beeper-cli/internal/api"
+	"github.com/salmonumbrella/beeper-cli/internal/testutil"
+)
+
+func event(id, text string) api.MessageEvent {
+	return api.MessageEvent{
+		Message: api.Message{ID: id, ChatID: "c1", AccountID: "slack-acct", Text: text, Timestamp: time.Now()},
+		Chat:    &api.Chat{ID: "c1", Title: "Incidents", Network: "Slack"},
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	own := event("m1", "SEV1 outage")
+	own.IsMe = true
+
+	tests := []struct {
+		name   string
+		filter Filter
+		ev     api.MessageEvent
+		want   bool
+	}{
+		{"empty filter", Filter{}, event("m1", "hi"), true},
+		{"regex hit", Filter{Match: regexp.MustCompile(`(?i)\bsev[12]\b`)}, event("m1", "sev2 in prod"), true},
+		{"regex miss", Filter{Match: regexp.MustCompile(`(?i)\bsev[12]\b`)}, event("m1", "several"), false},
+		{"network name", Filter{Networks: []string{"slack"}}, event("m1", "hi"), true},
+		{"account id", Filter{Networks: []string{"SLACK-ACCT"}}, event("m1", "hi"), true},
+		{"other network", Filter{Networks: []string{"whatsapp"}}, event("m1", "hi"), false},
+		{"own message", Filter{}, own, false},
+		{"own included", Filter{IncludeOwn: true}, own, true},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Matches(tt.ev); got != tt.want {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStatePersistsAcrossLoads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watch", "oncall.json")
+	s, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	ts := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := s.Mark(api.Message{ID: "m1", Timestamp: ts}); err != nil {
+		t.Fatalf("Mark() error: %v", err)
+	}
+
+	again, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	if !again.Seen("m1") || again.Seen("m2") {
+		t.Errorf("reloaded state lost handled IDs: %+v", again)
+	}
+	if !again.LastSeen.Equal(ts) {
+		t.Errorf("LastSeen = %v, want %v", again.LastSeen, ts)
+	}
+}
+
+func TestStateIsBounded(t *testing.T) {
+	s, _ := LoadState(filepath.Join(t.TempDir(), "s.json"))
+	// Fill the state directly to avoid thousands of disk writes.
+	for i := range stateLimit {
+		id := fmt.Sprintf("m%d", i)
+		s.set[id] = true
+		s.Handled = append(s.Handled, id)
+	}
+	if err := s.Mark(api.Message{ID: "last"}); err != nil {
+		t.Fatalf("Mark() error: %v", err)
+	}
+	if len(s.Handled) != stateLimit || len(s.set) != stateLimit {
+		t.Errorf("state holds %d/%d IDs, want %d", len(s.Handled), len(s.set), stateLimit)
+	}
+	if !s.Seen("last") || s.Seen("m0") || !s.Seen("m1") {
+		t.Error("only the oldest ID should be dropped")
+	}
+}
+
+type countingHook struct {
+	calls int
+	err   error
+}
+
+func (h *countingHook) Run(context.Context, Payload) error {
+	h.calls++
+	return h.err
+}
+
+func TestWatcherHandlesOnce(t *testing.T) {
+	hook := &countingHook{}
+	state, _ := LoadState(filepath.Join(t.TempDir(), "s.json"))
+	w := &Watcher{Filter: Filter{Match: regexp.MustCompile("deploy")}, Hooks: []Hook{hook}, State: state}
+
+	for range 2 {
+		if _, err := w.Handle(context.Background(), event("m1", "deploy done")); err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+	}
+	if ran, _ := w.Handle(context.Background(), event("m2", "lunch?")); ran {
+		t.Error("non-matching message ran hooks")
+	}
+	if hook.calls != 1 {
+		t.Errorf("hook ran %d times, want 1", hook.calls)
+	}
+}
+
+func TestWatcherRetriesThenGivesUp(t *testing.T) {
+	hook := &countingHook{err: errors.New("down")}
+	state, _ := LoadState(filepath.Join(t.TempDir(), "s.json"))
+	w := &Watcher{Hooks: []Hook{hook}, State: state, Attempts: 3, RetryDelay: time.Millisecond}
+
+	ran, err := w.Handle(context.Background(), event("m1", "x"))
+	if !ran || err == nil {
+		t.Fatalf("Handle() = %v, %v; want hook failure reported", ran, err)
+	}
+	if hook.calls != 3 {
+		t.Errorf("hook ran %d times, want 3", hook.calls)
+	}
+	if !state.Seen("m1") {
+		t.Error("failed message should still be marked handled")
+	}
+}
+
+func TestWebhookHookPostsPayload(t *testing.T) {
+	var got Payload
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ev := event("m1", "SEV1")
+	ev.SenderID = "@ann:slack"
+	ev.Chat.Participants = &api.ParticipantList{Items: []api.Participant{{ID: "@ann:slack", FullName: "Ann Lee"}}}
+	p := NewPayload(ev)
+	if err := (WebhookHook{URL: server.URL}).Run(context.Background(), p); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if got.ID != "m1" || got.ChatTitle != "Incidents" || got.Text != "SEV1" || got.SenderName != "Ann Lee" {
+		t.Errorf("payload = %+v", got)
+	}
+}
+
+func TestWebhookHookReportsStatus(t *testing.T) {
+	server := testutil.NewMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	if err := (WebhookHook{URL: server.URL}).Run(context.Background(), NewPayload(event("m1", "x"))); err == nil {
+		t.Error("Run() should fail on a 500")
+	}
+}
+
+func TestExecHookReceivesPayload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	hook := ExecHook{Command: `cat > "$OUT"; echo "$BEEPER_CHAT_TITLE" >> "$OUT"`}
+	t.Setenv("OUT", out)
+
+	if err := hook.Run(context.Background(), NewPayload(event("m1", "SEV1"))); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook wrote nothing: %v", err)
+	}
+	if !strings.Contains(string(data), `"chatTitle":"Incidents"`) || !strings.HasSuffix(string(data), "Incidents\n") {
+		t.Errorf("hook output = %s", data)
+	}
+}